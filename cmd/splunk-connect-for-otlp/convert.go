@@ -0,0 +1,301 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/splunk/otlp2splunk/internal"
+	"github.com/splunk/otlp2splunk/internal/exporter/stdoutexporter"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configoptional"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	noopmetric "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+)
+
+// stdinPath names the standard input in the paths of the convert command.
+const stdinPath = "-"
+
+// convert writes the HEC events the input would write for OTLP files, so the output of a payload can be inspected
+// or golden files regenerated. Files are OTLP JSON, possibly one request per line, or OTLP protobuf.
+func convert(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	output := flags.String("output", "", "file the HEC events are written to, instead of stdout")
+	signal := flags.String("signal", "", "signal of the OTLP files: logs, metrics or traces. Detected for JSON when empty, required for protobuf")
+	index := flags.String("index", "", "index of events without a com.splunk.index attribute")
+	sourceType := flags.String("sourcetype", "", "sourcetype of events without a com.splunk.sourcetype attribute")
+	source := flags.String("source", "", "source of events without a com.splunk.source attribute")
+	host := flags.String("host", "", "host of events without a host.name attribute")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	switch *signal {
+	case "", "logs", "metrics", "traces":
+	default:
+		return fmt.Errorf("signal %q is not supported, it must be one of logs, metrics or traces", *signal)
+	}
+	paths, err := expandPaths(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	w := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		w = f
+	}
+
+	cfg := stdoutexporter.NewFactory().CreateDefaultConfig().(*stdoutexporter.Config)
+	// Write events as they are translated, so the output follows the order of the files.
+	cfg.QueueBatchConfig = configoptional.None[exporterhelper.QueueBatchConfig]()
+	cfg.StreamingMode = stdoutexporter.StreamingModeSimple
+	cfg.Index = *index
+	cfg.SourceType = *sourceType
+	cfg.Source = *source
+	cfg.Host = *host
+	cfg.Output = w
+
+	ctx := context.Background()
+	c, err := newConverter(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, path := range paths {
+		data, err := readPath(path, stdin)
+		if err == nil {
+			err = c.convert(ctx, data, *signal)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot convert %s: %w", path, err))
+		}
+	}
+	return errors.Join(append(errs, c.shutdown(ctx))...)
+}
+
+// expandPaths returns the files named by args, which are files, directories or glob patterns. The files of
+// directories and the files matching patterns are sorted by name. No argument stands for the standard input.
+func expandPaths(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return []string{stdinPath}, nil
+	}
+	var paths []string
+	for _, arg := range args {
+		if arg == stdinPath {
+			paths = append(paths, arg)
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no file matches %s", arg)
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+			if !info.IsDir() {
+				paths = append(paths, match)
+				continue
+			}
+			entries, err := os.ReadDir(match)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if entry.Type().IsRegular() {
+					paths = append(paths, filepath.Join(match, entry.Name()))
+				}
+			}
+		}
+	}
+	return paths, nil
+}
+
+func readPath(path string, stdin io.Reader) ([]byte, error) {
+	if path == stdinPath {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// converter translates OTLP data with the exporters of the input.
+type converter struct {
+	logs    exporter.Logs
+	metrics exporter.Metrics
+	traces  exporter.Traces
+}
+
+func newConverter(ctx context.Context, cfg *stdoutexporter.Config) (*converter, error) {
+	f := stdoutexporter.NewFactory()
+	set := exporter.Settings{
+		ID: component.NewIDWithName(f.Type(), "convert"),
+		TelemetrySettings: component.TelemetrySettings{
+			Logger:         zap.NewNop(),
+			TracerProvider: noop.NewTracerProvider(),
+			MeterProvider:  noopmetric.NewMeterProvider(),
+			Resource:       pcommon.NewResource(),
+		},
+	}
+	c := &converter{}
+	var err error
+	if c.logs, err = f.CreateLogs(ctx, set, cfg); err != nil {
+		return nil, err
+	}
+	if c.metrics, err = f.CreateMetrics(ctx, set, cfg); err != nil {
+		return nil, err
+	}
+	if c.traces, err = f.CreateTraces(ctx, set, cfg); err != nil {
+		return nil, err
+	}
+	host := &internal.TTYHost{Extensions: map[component.ID]component.Component{}}
+	for _, exp := range []component.Component{c.logs, c.metrics, c.traces} {
+		if err = exp.Start(ctx, host); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// convert writes the events of an OTLP payload. The signal of the payload is detected when empty.
+func (c *converter) convert(ctx context.Context, data []byte, signal string) error {
+	payloads, err := decodeOTLP(data, signal)
+	if err != nil {
+		return err
+	}
+	for _, payload := range payloads {
+		switch p := payload.(type) {
+		case plog.Logs:
+			err = c.logs.ConsumeLogs(ctx, p)
+		case pmetric.Metrics:
+			err = c.metrics.ConsumeMetrics(ctx, p)
+		case ptrace.Traces:
+			err = c.traces.ConsumeTraces(ctx, p)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *converter) shutdown(ctx context.Context) error {
+	return errors.Join(c.logs.Shutdown(ctx), c.metrics.Shutdown(ctx), c.traces.Shutdown(ctx))
+}
+
+// decodeOTLP decodes the OTLP requests of data: JSON requests, one after the other, or a protobuf request.
+// It returns plog.Logs, pmetric.Metrics or ptrace.Traces values.
+func decodeOTLP(data []byte, signal string) ([]any, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	// The tag and length of the first field of a protobuf request may read as whitespace and an opening brace,
+	// so data that is not JSON is decoded as protobuf.
+	payloads, err := decodeJSONRequests(data, signal)
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return payloads, err
+	}
+	payload, protoErr := decodeProto(data, signal)
+	if protoErr != nil {
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+			if signal == "" {
+				return nil, fmt.Errorf("%w, or the request is protobuf and its signal must be set with --signal", err)
+			}
+			return nil, err
+		}
+		return nil, protoErr
+	}
+	return []any{payload}, nil
+}
+
+// decodeJSONRequests decodes OTLP JSON requests written one after the other, usually one per line.
+func decodeJSONRequests(data []byte, signal string) ([]any, error) {
+	var payloads []any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		var request json.RawMessage
+		if err := decoder.Decode(&request); err != nil {
+			return nil, err
+		}
+		payload, err := decodeJSON(request, signal)
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, payload)
+	}
+	return payloads, nil
+}
+
+// decodeJSON decodes an OTLP JSON request, detecting its signal from its top-level field when signal is empty.
+func decodeJSON(request []byte, signal string) (any, error) {
+	if signal == "" {
+		var fields struct {
+			ResourceLogs    json.RawMessage `json:"resourceLogs"`
+			ResourceMetrics json.RawMessage `json:"resourceMetrics"`
+			ResourceSpans   json.RawMessage `json:"resourceSpans"`
+		}
+		if err := json.Unmarshal(request, &fields); err != nil {
+			return nil, err
+		}
+		switch {
+		case fields.ResourceLogs != nil:
+			signal = "logs"
+		case fields.ResourceMetrics != nil:
+			signal = "metrics"
+		case fields.ResourceSpans != nil:
+			signal = "traces"
+		default:
+			return nil, errors.New("cannot detect the signal of the request, it has none of resourceLogs, resourceMetrics or resourceSpans")
+		}
+	}
+	switch signal {
+	case "logs":
+		return (&plog.JSONUnmarshaler{}).UnmarshalLogs(request)
+	case "metrics":
+		return (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(request)
+	default:
+		return (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(request)
+	}
+}
+
+// decodeProto decodes an OTLP protobuf request of signal. The signal of a protobuf request cannot be detected: the
+// requests of all signals start with resources and scopes encoded alike, an empty request has no field at all, and
+// a request of a signal may decode without error as another.
+func decodeProto(request []byte, signal string) (any, error) {
+	switch signal {
+	case "logs":
+		return (&plog.ProtoUnmarshaler{}).UnmarshalLogs(request)
+	case "metrics":
+		return (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(request)
+	case "traces":
+		return (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(request)
+	default:
+		return nil, errors.New("the signal of protobuf requests cannot be detected, set it with --signal")
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/splunk/otlp2splunk/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestConvertJSON(t *testing.T) {
+	for _, signal := range []string{"logs", "metrics", "traces"} {
+		t.Run(signal, func(t *testing.T) {
+			expected := testutils.LoadExpectedHecData(t, filepath.Join("testdata", "expected_hec_"+signal+".json"))
+
+			var stdout bytes.Buffer
+			require.NoError(t, convert([]string{filepath.Join("testdata", "otlp_"+signal+".json")}, nil, &stdout))
+			require.Equal(t, string(expected), stdout.String())
+		})
+	}
+}
+
+func TestConvertProtoDirectory(t *testing.T) {
+	for _, signal := range []string{"logs", "metrics", "traces"} {
+		dir := t.TempDir()
+		payload, err := os.ReadFile(filepath.Join("testdata", "otlp_"+signal+".json"))
+		require.NoError(t, err)
+		var proto []byte
+		switch signal {
+		case "logs":
+			logs, jsonErr := (&plog.JSONUnmarshaler{}).UnmarshalLogs(payload)
+			require.NoError(t, jsonErr)
+			proto, err = (&plog.ProtoMarshaler{}).MarshalLogs(logs)
+		case "metrics":
+			metrics, jsonErr := (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(payload)
+			require.NoError(t, jsonErr)
+			proto, err = (&pmetric.ProtoMarshaler{}).MarshalMetrics(metrics)
+		case "traces":
+			traces, jsonErr := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(payload)
+			require.NoError(t, jsonErr)
+			proto, err = (&ptrace.ProtoMarshaler{}).MarshalTraces(traces)
+		}
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, signal+".pb"), proto, 0o600))
+		expected := testutils.LoadExpectedHecData(t, filepath.Join("testdata", "expected_hec_"+signal+".json"))
+
+		output := filepath.Join(t.TempDir(), "hec.json")
+		require.NoError(t, convert([]string{"--signal", signal, "--output", output, dir}, nil, nil))
+		actual, err := os.ReadFile(output)
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(actual))
+
+		// The signal of protobuf requests is never guessed.
+		err = convert([]string{"--output", output, dir}, nil, nil)
+		require.ErrorContains(t, err, "cannot convert "+filepath.Join(dir, signal+".pb"))
+		require.ErrorContains(t, err, "with --signal")
+	}
+}
+
+func TestConvertStdin(t *testing.T) {
+	requests := `{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"first"}}]}]}]}
+{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"requests","gauge":{"dataPoints":[{"asInt":"3"}]}}]}]}]}
+`
+	var stdout bytes.Buffer
+	require.NoError(t, convert([]string{"--index", "otlp", "--host", "edge-1"}, strings.NewReader(requests), &stdout))
+	require.Equal(t, `{"event":"first","host":"edge-1","index":"otlp"}
+{"event":"metric","fields":{"metric_name:requests":3,"metric_type":"Gauge"},"host":"edge-1","index":"otlp"}
+`, stdout.String())
+}
+
+func TestConvertErrors(t *testing.T) {
+	require.EqualError(t, convert([]string{filepath.Join("testdata", "missing_*.json")}, nil, nil),
+		"no file matches "+filepath.Join("testdata", "missing_*.json"))
+	require.EqualError(t, convert([]string{"--signal", "profiles"}, nil, nil),
+		`signal "profiles" is not supported, it must be one of logs, metrics or traces`)
+	require.EqualError(t, convert(nil, strings.NewReader(`{"resourceProfiles":[]}`), &bytes.Buffer{}),
+		"cannot convert -: cannot detect the signal of the request, it has none of resourceLogs, resourceMetrics or resourceSpans")
+}
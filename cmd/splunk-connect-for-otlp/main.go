@@ -1,26 +1,29 @@
 // Copyright Splunk Inc. 2025
 // SPDX-License-Identifier: Apache-2.0
 
-// Program splunk-connect-for-otlp is a binary listening for OTLP data and exporting it to stdout.
+// Program splunk-connect-for-otlp is a binary listening for OTLP data and exporting it to stdout or to HEC.
 package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/splunk/otlp2splunk/internal"
-	"github.com/splunk/otlp2splunk/internal/exporter/stdoutexporter"
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/confmap"
-	"go.opentelemetry.io/collector/exporter"
-	"go.opentelemetry.io/collector/pdata/pcommon"
-	"go.opentelemetry.io/collector/receiver"
-	"go.opentelemetry.io/collector/receiver/otlpreceiver"
-	noopmetric "go.opentelemetry.io/otel/metric/noop"
-	"go.opentelemetry.io/otel/trace/noop"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func main() {
@@ -34,115 +37,279 @@ func main() {
 		case "--scheme":
 			fmt.Println(internal.Scheme)
 		case "--validate-arguments":
-
+			if err := validateArguments(); err != nil {
+				fmt.Println(internal.FormatValidationError(err))
+				os.Exit(1)
+			}
+		case "serve":
+			if err := serve(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+		case "convert":
+			if err := convert(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+				log.Fatal(err)
+			}
+		case "replay":
+			if err := replay(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+				log.Fatal(err)
+			}
+		default:
+			log.Fatalf("unknown command %q, expected --scheme, --validate-arguments, serve, convert or replay", os.Args[1])
 		}
 	} else if err := run(); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func run() error {
+func validateArguments() error {
+	config, err := internal.ReadFromStdin()
+	if err != nil {
+		return fmt.Errorf("cannot read validation request: %w", err)
+	}
+	return config.Validate()
+}
+
+// run serves the input stanzas splunkd sends on stdin. The input runs in single instance mode: every stanza is
+// served by the same process, with its own pipeline.
+func run() (err error) {
 	defer func() {
+		// Report panics as errors, on stderr: stdout carries the events read by splunkd.
 		if r := recover(); r != nil {
-			fmt.Println("Recovered from panic:", r)
-			fmt.Println("Stack trace:")
-			fmt.Println(string(debug.Stack()))
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
 		}
 	}()
 	config, err := internal.ReadFromStdin()
 	if err != nil {
 		return err
 	}
+	inputs := config.Inputs()
+	if len(inputs) == 0 {
+		return errors.New("splunkd did not send any input stanza")
+	}
 
-	logger, err := internal.CreateLogger()
+	ctx := context.Background()
+	var logger *zap.Logger
+	if len(inputs) > 1 {
+		if logger, _, err = internal.CreateLogger(internal.LogSettings{Level: zapcore.InfoLevel, Format: internal.DefaultLogFormat}); err != nil {
+			return err
+		}
+		logger.Info("Starting OTLP inputs", zap.Int("inputs", len(inputs)))
+	}
+	// The components of a single stanza keep their plain names.
+	pipelines, err := newPipelines(ctx, inputs, len(inputs) > 1)
 	if err != nil {
 		return err
 	}
-	logger.Info("Starting OTLP input")
-
-	settings := component.TelemetrySettings{
-		Logger:         logger,
-		TracerProvider: noop.NewTracerProvider(),
-		MeterProvider:  noopmetric.NewMeterProvider(),
-		Resource:       pcommon.NewResource(),
+	if logger == nil {
+		logger = pipelines[0].logger
 	}
-
-	grpcPort, httpPort, listeningAddress := config.Extract()
-	stdoutCfg := stdoutexporter.NewFactory().CreateDefaultConfig().(*stdoutexporter.Config)
-	f := stdoutexporter.NewFactory()
-	ctx := context.Background()
-	telemetrySettings := exporter.Settings{
-		TelemetrySettings: settings,
-		ID:                component.MustNewID("stdout"),
+	// The stanzas are read again from Splunk, with the session key splunkd handed to the input. Polling follows
+	// the shortest reload_interval of the stanzas.
+	var interval time.Duration
+	for _, p := range pipelines {
+		if i := p.settings.ReloadInterval; i > 0 && (interval == 0 || i < interval) {
+			interval = i
+		}
 	}
-	le, err := f.CreateLogs(ctx, telemetrySettings, stdoutCfg)
-	if err != nil {
+	load := func(ctx context.Context) ([]internal.XMLInput, error) {
+		fetched := make([]internal.XMLInput, 0, len(inputs))
+		for _, input := range inputs {
+			input, err := input.FetchStanza(ctx)
+			if err != nil {
+				return nil, err
+			}
+			fetched = append(fetched, input)
+		}
+		return fetched, nil
+	}
+	return servePipelines(ctx, logger, pipelines, load, interval)
+}
+
+// serve runs the inputs of a configuration file as a standalone daemon, outside splunkd.
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	configPath := flags.String("config", "", "path to the YAML configuration file of the inputs")
+	if err := flags.Parse(args); err != nil {
 		return err
 	}
-	me, err := f.CreateMetrics(ctx, telemetrySettings, stdoutCfg)
+	if *configPath == "" {
+		return errors.New("serve requires --config")
+	}
+	fileConfig, err := internal.LoadFileConfig(*configPath)
 	if err != nil {
 		return err
 	}
-	te, err := f.CreateTraces(ctx, telemetrySettings, stdoutCfg)
+	inputs, err := fileConfig.XMLInputs()
 	if err != nil {
 		return err
 	}
-	logger.Info("Configured exporter")
-
-	rf := otlpreceiver.NewFactory()
-	cfg := rf.CreateDefaultConfig().(*otlpreceiver.Config)
-	_ = cfg.GRPC.Unmarshal(confmap.NewFromStringMap(map[string]any{"endpoint": fmt.Sprintf("%s:%d", listeningAddress, grpcPort)}))
-	_ = cfg.HTTP.Unmarshal(confmap.NewFromStringMap(map[string]any{"endpoint": fmt.Sprintf("%s:%d", listeningAddress, httpPort)}))
 
-	if _, err = rf.CreateLogs(ctx, receiver.Settings{
-		TelemetrySettings: settings,
-		ID:                component.MustNewID("otlp"),
-	}, cfg, le); err != nil {
-		return err
-	}
-	if _, err = rf.CreateMetrics(ctx, receiver.Settings{
-		TelemetrySettings: settings,
-		ID:                component.MustNewID("otlp"),
-	}, cfg, me); err != nil {
+	logger, _, err := internal.CreateLogger(internal.LogSettings{Level: zapcore.InfoLevel, Format: internal.DefaultLogFormat})
+	if err != nil {
 		return err
 	}
-	r, err := rf.CreateTraces(ctx, receiver.Settings{
-		TelemetrySettings: settings,
-		ID:                component.MustNewID("otlp"),
-	}, cfg, te)
+	logger.Info("Starting OTLP inputs", zap.String("config", *configPath), zap.Int("inputs", len(inputs)))
+
+	ctx := context.Background()
+	pipelines, err := newPipelines(ctx, inputs, true)
 	if err != nil {
 		return err
 	}
+	load := func(context.Context) ([]internal.XMLInput, error) {
+		fileConfig, err := internal.LoadFileConfig(*configPath)
+		if err != nil {
+			return nil, err
+		}
+		return fileConfig.XMLInputs()
+	}
+	return servePipelines(ctx, logger, pipelines, load, 0)
+}
 
-	logger.Info("Configured OTLP receiver")
+// newPipelines creates a pipeline per input. Components are named after their input when named is true, so the
+// components of several inputs can be told apart. An input which cannot be created, or which listens on the same
+// port as another input, is skipped, so it does not stop the other inputs: newPipelines fails only when no
+// pipeline can be created.
+func newPipelines(ctx context.Context, inputs []internal.XMLInput, named bool) ([]*pipeline, error) {
+	pipelines := make([]*pipeline, 0, len(inputs))
+	settings := make([]internal.Settings, 0, len(inputs))
+	var errs []error
+	for _, input := range inputs {
+		var name string
+		if named {
+			name = strings.TrimPrefix(input.Configuration.Stanza.Name, internal.StanzaScheme)
+		}
+		p, err := newPipeline(ctx, input, name)
+		if err == nil {
+			if err = internal.CheckListeners(append(slices.Clip(settings), p.settings)); err != nil {
+				p.logger.Error("Refusing to start OTLP input, it listens on the port of another input", zap.Error(err))
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		pipelines = append(pipelines, p)
+		settings = append(settings, p.settings)
+	}
+	if len(pipelines) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return pipelines, nil
+}
 
+// servePipelines starts the pipelines and shuts them down once the process is asked to stop. The pipelines
+// reload the configuration returned by load on SIGHUP, and every interval when it is not 0.
+func servePipelines(ctx context.Context, logger *zap.Logger, pipelines []*pipeline, load loadFunc, interval time.Duration) error {
 	h := &internal.TTYHost{
 		ErrStatus:  make(chan error, 1),
 		Extensions: map[component.ID]component.Component{},
 	}
 	h.Start()
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
 
-	if err = le.Start(ctx, h); err != nil {
-		return err
-	}
-	if err = me.Start(ctx, h); err != nil {
-		return err
-	}
-	if err = te.Start(ctx, h); err != nil {
-		return err
+	// A pipeline failing to start is shut down, and the other pipelines run without it.
+	started := make([]*pipeline, 0, len(pipelines))
+	var startErrs []error
+	for _, p := range pipelines {
+		if err := p.start(ctx, h); err != nil {
+			p.abort(ctx, h)
+			if len(pipelines) > 1 {
+				p.logger.Error("Cannot start OTLP input, the other inputs run without it", zap.Error(err))
+			}
+			startErrs = append(startErrs, err)
+			continue
+		}
+		started = append(started, p)
 	}
-	if err = r.Start(ctx, h); err != nil {
-		return err
+	if len(started) == 0 {
+		return errors.Join(startErrs...)
 	}
+	pipelines = started
 
 	logger.Info("OTLP Input started")
 
-	err = h.Wait()
+	// Reloads run one at a time, and are over before the pipelines shut down.
+	stopped := make(chan struct{})
+	reloaded := make(chan struct{})
+	go func() {
+		defer close(reloaded)
+		var tick <-chan time.Time
+		if interval > 0 {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-hup:
+				logger.Info("Reloading the input configuration")
+			case <-tick:
+			case <-stopped:
+				return
+			}
+			reloadPipelines(ctx, h, logger, pipelines, load)
+		}
+	}()
+
+	err := h.Wait()
+	close(stopped)
+	<-reloaded
+	logger.Info("Stopping OTLP Input")
+
+	// Pipelines drain concurrently, so each gets its whole shutdown timeout.
+	errs := make([]error, len(pipelines)+1)
+	errs[0] = err
+	var wg sync.WaitGroup
+	for i, p := range pipelines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i+1] = p.shutdown(ctx, h)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
 
-	_ = r.Shutdown(ctx)
-	_ = le.Shutdown(ctx)
-	_ = te.Shutdown(ctx)
-	_ = me.Shutdown(ctx)
+// startComponent starts c, reporting its status to the host like the collector does.
+func startComponent(ctx context.Context, h *internal.TTYHost, id component.ID, c component.Component) error {
+	h.ReportComponentStatus(id, componentstatus.NewEvent(componentstatus.StatusStarting))
+	if err := c.Start(ctx, h.ForComponent(id)); err != nil {
+		h.ReportComponentStatus(id, componentstatus.NewPermanentErrorEvent(err))
+		return err
+	}
+	h.ReportComponentStatus(id, componentstatus.NewEvent(componentstatus.StatusOK))
+	return nil
+}
 
-	return err
+// shutdownComponent shuts c down, reporting its status to the host like the collector does. It stops waiting
+// for c once ctx is done, leaving c to shut down in the background.
+func shutdownComponent(ctx context.Context, h *internal.TTYHost, id component.ID, c component.Component) error {
+	h.ReportComponentStatus(id, componentstatus.NewEvent(componentstatus.StatusStopping))
+	if err := shutdownWithin(ctx, id, c); err != nil {
+		h.ReportComponentStatus(id, componentstatus.NewPermanentErrorEvent(err))
+		return err
+	}
+	h.ReportComponentStatus(id, componentstatus.NewEvent(componentstatus.StatusStopped))
+	return nil
+}
+
+// shutdownWithin shuts c down, without waiting for it once ctx is done.
+func shutdownWithin(ctx context.Context, id component.ID, c component.Component) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Shutdown(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("cannot shut %s down: %w", id, err)
+	}
+	return nil
 }
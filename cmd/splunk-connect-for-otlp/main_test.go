@@ -4,17 +4,39 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
 
+	"github.com/golang/snappy"
+	jaegermodel "github.com/jaegertracing/jaeger-idl/model/v1"
+	"github.com/jaegertracing/jaeger-idl/proto-gen/api_v2"
 	"github.com/splunk/otlp2splunk/internal"
 	"github.com/splunk/otlp2splunk/internal/testutils"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tinylib/msgp/msgp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protowire"
 )
 
 func TestMainPrintsScheme(t *testing.T) {
@@ -40,20 +62,19 @@ func TestRunReturnsErrorForInvalidInput(t *testing.T) {
 }
 
 func TestRunStartsAndStopsOnSignal(t *testing.T) {
-	restoreStdin := testutils.WriteToStdin(t, `<input><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">0</param><param name="http_port">0</param><param name="listen_address">127.0.0.1</param></stanza></configuration></input>`)
+	restoreStdin := testutils.WriteToStdin(t, fmt.Sprintf(`<input><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param></stanza></configuration></input>`,
+		testutils.GetFreePort(t), testutils.GetFreePort(t)))
 	defer restoreStdin()
 
-	done := make(chan error, 1)
+	stop := startInput(t, run)
+	time.Sleep(500 * time.Millisecond)
+
+	stopped := make(chan error, 1)
 	go func() {
-		done <- run()
+		stopped <- stop()
 	}()
-
-	time.AfterFunc(500*time.Millisecond, func() {
-		_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
-	})
-
 	select {
-	case err := <-done:
+	case err := <-stopped:
 		require.NoError(t, err)
 	case <-time.After(5 * time.Second):
 		t.Fatal("run did not complete in time")
@@ -108,36 +129,1271 @@ func TestExpectedHEC(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			grpcPort := testutils.GetFreePort(t)
+			// The large files are generated locally and are not part of the repository.
+			for _, path := range []string{tt.inputPath, tt.expectedPath} {
+				if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+					t.Skipf("%s does not exist", path)
+				}
+			}
+			payload, err := os.ReadFile(tt.inputPath)
+			require.NoError(t, err)
+			expected := testutils.LoadExpectedHecData(t, tt.expectedPath)
+			expectedLines := strings.Split(strings.TrimSpace(string(expected)), "\n")
+			require.NotEmpty(t, expectedLines, "%s must contain fixture data", tt.expectedPath)
+
 			httpPort := testutils.GetFreePort(t)
+			stdoutLines, stop := startRun(t, runStanza{httpPort: httpPort})
 
-			config := fmt.Sprintf(`<input><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param></stanza></configuration></input>`, grpcPort, httpPort)
+			testutils.PostOTLP(t, httpPort, tt.otlpendpoint, payload)
+			require.Equal(t, expectedLines, testutils.CollectEvents(t, stdoutLines, len(expectedLines)))
 
-			restoreStdin := testutils.WriteToStdin(t, config)
-			t.Cleanup(restoreStdin)
+			stop()
+		})
+	}
+}
 
-			stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
-			t.Cleanup(restoreStdout)
+// startInput runs fn, which runs or serves the input, in the background. It returns a function stopping the input
+// with SIGTERM and returning the error of fn. The input is also stopped when the test ends, so a failing test
+// does not leave it running, reading the signals and the standard streams of the tests after it.
+func startInput(t *testing.T, fn func() error) func() error {
+	t.Helper()
 
-			runDone := make(chan error, 1)
-			go func() {
-				runDone <- run()
-			}()
+	// The process is not terminated by the signals sent before the input listens for them.
+	signal.Notify(ignoredSignals, syscall.SIGTERM)
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+	var once sync.Once
+	var err error
+	stop := func() error {
+		once.Do(func() {
+			// The input may not listen for signals yet, so SIGTERM is sent until fn returns.
+			for {
+				select {
+				case err = <-done:
+					return
+				default:
+				}
+				_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+				select {
+				case err = <-done:
+					return
+				case <-time.After(time.Second):
+				}
+			}
+		})
+		return err
+	}
+	t.Cleanup(func() {
+		_ = stop()
+	})
+	return stop
+}
 
-			payload, err := os.ReadFile(tt.inputPath)
+// ignoredSignals receives the signals sent to inputs which do not listen for them yet.
+var ignoredSignals = make(chan os.Signal, 1)
+
+// runStanza is the stanza startRun serves.
+type runStanza struct {
+	// params are the parameters of the receivers under test.
+	params string
+	// httpPort is the port of the OTLP/HTTP receiver, a free port when 0.
+	httpPort int
+	// splunkd is the URL of the Splunk management API the input reads its secrets from.
+	splunkd string
+	// token is presented by the OTLP request checking that the input started.
+	token string
+	// tlsFiles are the certificate files the receivers serve, when not nil.
+	tlsFiles *testutils.TLSFiles
+}
+
+// startRun runs the input for a stanza listening on 127.0.0.1, and returns once the input writes the events of
+// an OTLP request. It returns the lines the input writes to stdout, and a function stopping the input.
+func startRun(t *testing.T, input runStanza) (<-chan string, func()) {
+	t.Helper()
+
+	if input.httpPort == 0 {
+		input.httpPort = testutils.GetFreePort(t)
+	}
+	params := input.params
+	scheme, client := "http", http.DefaultClient
+	if input.tlsFiles != nil {
+		params += fmt.Sprintf(`<param name="cert_file">%s</param><param name="key_file">%s</param>`, input.tlsFiles.CertFile, input.tlsFiles.KeyFile)
+		scheme, client = "https", &http.Client{Transport: &http.Transport{TLSClientConfig: input.tlsFiles.ClientTLSConfig(t, false)}}
+	}
+	var session string
+	if input.splunkd != "" {
+		session = fmt.Sprintf(`<server_uri>%s</server_uri><session_key>session</session_key>`, input.splunkd)
+	}
+	config := fmt.Sprintf(`<input>%s<configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param>%s</stanza></configuration></input>`,
+		session, testutils.GetFreePort(t), input.httpPort, params)
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, run)
+
+	// The receivers start together, so every receiver listens once the OTLP receiver accepts requests.
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s://127.0.0.1:%d/v1/logs", scheme, input.httpPort),
+			strings.NewReader(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"hello"}}]}]}]}`))
+		require.NoError(c, err)
+		req.Header.Set("Content-Type", "application/json")
+		if input.token != "" {
+			req.Header.Set("Authorization", "Bearer "+input.token)
+		}
+		resp, err := client.Do(req)
+		if !assert.NoError(c, err) {
+			return
+		}
+		_ = resp.Body.Close()
+		assert.Equal(c, http.StatusOK, resp.StatusCode)
+	}, 5*time.Second, 100*time.Millisecond)
+	testutils.CollectEvents(t, stdoutLines, 1)
+
+	return stdoutLines, func() {
+		require.NoError(t, stop())
+	}
+}
+
+func TestExpectedHECFromZipkin(t *testing.T) {
+	httpPort := testutils.GetFreePort(t)
+	zipkinPort := testutils.GetFreePort(t)
+	zipkinPayload, err := os.ReadFile(filepath.Join("testdata", "zipkin_traces.json"))
+	require.NoError(t, err)
+	otlpPayload, err := os.ReadFile(filepath.Join("testdata", "otlp_zipkin_traces.json"))
+	require.NoError(t, err)
+	expected := testutils.LoadExpectedHecData(t, filepath.Join("testdata", "expected_hec_zipkin_traces.json"))
+	expectedLines := strings.Split(strings.TrimSpace(string(expected)), "\n")
+
+	stdoutLines, stop := startRun(t, runStanza{params: fmt.Sprintf(`<param name="zipkin_port">%d</param>`, zipkinPort), httpPort: httpPort})
+
+	// The OTLP equivalent of the Zipkin spans produces the same events.
+	testutils.PostOTLP(t, httpPort, "/v1/traces", otlpPayload)
+	require.Equal(t, expectedLines, testutils.CollectEvents(t, stdoutLines, len(expectedLines)))
+
+	resp, err := http.Post(fmt.Sprintf("http://127.0.0.1:%d/api/v2/spans", zipkinPort), "application/json", bytes.NewReader(zipkinPayload))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	require.Equal(t, expectedLines, testutils.CollectEvents(t, stdoutLines, len(expectedLines)))
+
+	stop()
+}
+
+func TestRunReceivesJaeger(t *testing.T) {
+	jaegerPort := testutils.GetFreePort(t)
+	expected := testutils.LoadExpectedHecData(t, filepath.Join("testdata", "expected_hec_zipkin_traces.json"))
+	expectedLines := strings.Split(strings.TrimSpace(string(expected)), "\n")
+
+	stdoutLines, stop := startRun(t, runStanza{params: fmt.Sprintf(`<param name="jaeger_grpc_port">%d</param>`, jaegerPort)})
+
+	conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", jaegerPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	traceID, err := jaegermodel.TraceIDFromString("5982fe77008310cc80f1da5e10147517")
+	require.NoError(t, err)
+	spanID, err := jaegermodel.SpanIDFromString("bcd1a4a5ecd3c2a5")
+	require.NoError(t, err)
+	_, err = api_v2.NewCollectorServiceClient(conn).PostSpans(t.Context(), &api_v2.PostSpansRequest{Batch: jaegermodel.Batch{
+		Process: jaegermodel.NewProcess("frontend", nil),
+		Spans: []*jaegermodel.Span{{
+			TraceID:       traceID,
+			SpanID:        spanID,
+			OperationName: "get /api/orders",
+			StartTime:     time.Unix(0, 1768515177026790000),
+			Duration:      1230 * time.Microsecond,
+			Tags: []jaegermodel.KeyValue{
+				jaegermodel.String("span.kind", "server"),
+				jaegermodel.String("http.method", "GET"),
+				jaegermodel.String("http.status_code", "200"),
+			},
+		}},
+	}})
+	require.NoError(t, err)
+	// The server span of the Zipkin golden file, sent with Jaeger, produces the same event.
+	require.Equal(t, expectedLines[1:], testutils.CollectEvents(t, stdoutLines, 1))
+
+	stop()
+}
+
+func TestExpectedHECRoundTrip(t *testing.T) {
+	for _, signal := range []string{"logs", "metrics"} {
+		t.Run(signal, func(t *testing.T) {
+			hecPort := testutils.GetFreePort(t)
+			// The events the input writes for OTLP data are posted back to the HEC receiver.
+			expected := testutils.LoadExpectedHecData(t, filepath.Join("testdata", "expected_hec_"+signal+".json"))
+			expectedLines := strings.Split(strings.TrimSpace(string(expected)), "\n")
+
+			stdoutLines, stop := startRun(t, runStanza{params: fmt.Sprintf(`<param name="hec_port">%d</param>`, hecPort)})
+
+			resp, err := http.Post(fmt.Sprintf("http://127.0.0.1:%d/services/collector/event", hecPort), "application/json", bytes.NewReader(expected))
 			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, expectedLines, testutils.CollectEvents(t, stdoutLines, len(expectedLines)))
 
-			expected := testutils.LoadExpectedHecData(t, tt.expectedPath)
+			stop()
+		})
+	}
+}
+
+func TestRunReceivesHECRaw(t *testing.T) {
+	splunkd := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/nobody/search/storage/passwords/splunk-connect-for-otlp:team_a:" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"entry":[{"name":"splunk-connect-for-otlp:team_a:","content":{"clear_password":"s3cr3t"}}]}`))
+	}))
+	defer splunkd.Close()
+
+	hecPort := testutils.GetFreePort(t)
+	stdoutLines, stop := startRun(t, runStanza{
+		params:  fmt.Sprintf(`<param name="hec_port">%d</param><param name="auth_tokens">team_a</param>`, hecPort),
+		splunkd: splunkd.URL,
+		token:   "s3cr3t",
+	})
+
+	post := func(authorization string) int {
+		req, reqErr := http.NewRequest(http.MethodPost, fmt.Sprintf("http://127.0.0.1:%d/services/collector/raw?index=main&sourcetype=app&host=web-1", hecPort), strings.NewReader("line one\nline two"))
+		require.NoError(t, reqErr)
+		req.Header.Set("Authorization", authorization)
+		resp, postErr := http.DefaultClient.Do(req)
+		require.NoError(t, postErr)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// HEC clients present their token with the Splunk scheme.
+	require.Equal(t, http.StatusUnauthorized, post("Bearer s3cr3t"))
+	require.Equal(t, http.StatusUnauthorized, post("Splunk wrong"))
+	require.Equal(t, http.StatusOK, post("Splunk s3cr3t"))
+	require.Equal(t, []string{
+		`{"event":"line one","host":"web-1","sourcetype":"app","index":"main"}`,
+		`{"event":"line two","host":"web-1","sourcetype":"app","index":"main"}`,
+	}, testutils.CollectEvents(t, stdoutLines, 2))
+
+	stop()
+}
+
+func TestRunReceivesSyslog(t *testing.T) {
+	files := testutils.WriteTLSFiles(t)
+	tcpPort := testutils.GetFreePort(t)
+	udpPort := testutils.GetFreePort(t)
+	stdoutLines, stop := startRun(t, runStanza{
+		params:   fmt.Sprintf(`<param name="syslog_tcp_port">%d</param><param name="syslog_udp_port">%d</param><param name="syslog_octet_counting">1</param>`, tcpPort, udpPort),
+		tlsFiles: &files,
+	})
+
+	// Octet counting frames the messages sent over TCP, so a message may span several lines.
+	messages := []string{
+		`<34>1 2025-01-02T03:04:05.678Z router-1 sshd 4242 ID47 [auth@32473 user="admin" method="password"] Failed password`,
+		"<165>1 2025-01-02T03:04:06Z router-1 kernel - - - link down\neth0",
+	}
+	conn, err := tls.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", tcpPort), files.ClientTLSConfig(t, false))
+	require.NoError(t, err)
+	for _, message := range messages {
+		_, err = fmt.Fprintf(conn, "%d %s", len(message), message)
+		require.NoError(t, err)
+	}
+	require.NoError(t, conn.Close())
+	require.Equal(t, []string{
+		`{"event":"\u003c34\u003e1 2025-01-02T03:04:05.678Z router-1 sshd 4242 ID47 [auth@32473 user=\"admin\" method=\"password\"] Failed password","fields":{"appname":"sshd","facility":4,"hostname":"router-1","message":"Failed password","msg_id":"ID47","otel.log.severity.number":18,"otel.log.severity.text":"crit","priority":34,"proc_id":"4242","structured_data.auth@32473.method":"password","structured_data.auth@32473.user":"admin","version":1},"host":"router-1","time":1735787045.678}`,
+		`{"event":"\u003c165\u003e1 2025-01-02T03:04:06Z router-1 kernel - - - link down\neth0","fields":{"appname":"kernel","facility":20,"hostname":"router-1","message":"link down\neth0","otel.log.severity.number":10,"otel.log.severity.text":"notice","priority":165,"version":1},"host":"router-1","time":1735787046}`,
+	}, testutils.CollectEvents(t, stdoutLines, 2))
+
+	udp, err := net.Dial("udp", fmt.Sprintf("127.0.0.1:%d", udpPort))
+	require.NoError(t, err)
+	_, err = udp.Write([]byte("<14>1 2025-01-02T03:04:07Z switch-2 lldpd - - - neighbor added"))
+	require.NoError(t, err)
+	require.NoError(t, udp.Close())
+	require.Equal(t, []string{
+		`{"event":"\u003c14\u003e1 2025-01-02T03:04:07Z switch-2 lldpd - - - neighbor added","fields":{"appname":"lldpd","facility":1,"hostname":"switch-2","message":"neighbor added","otel.log.severity.number":9,"otel.log.severity.text":"info","priority":14,"version":1},"host":"switch-2","time":1735787047}`,
+	}, testutils.CollectEvents(t, stdoutLines, 1))
+
+	stop()
+}
+
+// appendForwardEntry appends the [time, record] entry of a Forward message, with the time as an EventTime.
+func appendForwardEntry(b []byte, ts time.Time, record map[string]any) []byte {
+	b = msgp.AppendArrayHeader(b, 2)
+	b = append(b, 0xd7, 0x00)
+	b = binary.BigEndian.AppendUint32(b, uint32(ts.Unix()))
+	b = binary.BigEndian.AppendUint32(b, uint32(ts.Nanosecond()))
+	b, _ = msgp.AppendIntf(b, record)
+	return b
+}
+
+func TestRunReceivesFluentForward(t *testing.T) {
+	fluentPort := testutils.GetFreePort(t)
+	stdoutLines, stop := startRun(t, runStanza{params: fmt.Sprintf(`<param name="fluentforward_port">%d</param>`, fluentPort)})
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", fluentPort))
+	require.NoError(t, err)
+	defer conn.Close()
+	acks := msgp.NewReader(conn)
+	// send writes a Forward message with the chunk option, and waits for the receiver to acknowledge it.
+	send := func(chunk string, appendMessage func(b []byte) []byte) {
+		b := appendMessage(nil)
+		b = msgp.AppendMapHeader(b, 1)
+		b = msgp.AppendString(b, "chunk")
+		b = msgp.AppendString(b, chunk)
+		_, writeErr := conn.Write(b)
+		require.NoError(t, writeErr)
+		ack, readErr := acks.ReadIntf()
+		require.NoError(t, readErr)
+		require.Equal(t, map[string]any{"ack": chunk}, ack)
+	}
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 678000000, time.UTC)
+
+	// Message mode: [tag, time, record, options].
+	send("message", func(b []byte) []byte {
+		b = msgp.AppendArrayHeader(b, 4)
+		b = msgp.AppendString(b, "kube.var.log.containers.api")
+		b = msgp.AppendInt64(b, ts.Unix())
+		b, _ = msgp.AppendIntf(b, map[string]any{"log": "started", "stream": "stdout"})
+		return b
+	})
+	// Forward mode: [tag, [entries], options].
+	send("forward", func(b []byte) []byte {
+		b = msgp.AppendArrayHeader(b, 3)
+		b = msgp.AppendString(b, "kube.var.log.containers.web")
+		b = msgp.AppendArrayHeader(b, 2)
+		b = appendForwardEntry(b, ts, map[string]any{"log": "GET /", "stream": "stdout"})
+		b = appendForwardEntry(b, ts.Add(time.Second), map[string]any{"log": "GET /health", "stream": "stdout"})
+		return b
+	})
+	// PackedForward mode: [tag, entries as a binary stream, options].
+	send("packed", func(b []byte) []byte {
+		entries := appendForwardEntry(nil, ts, map[string]any{"message": "queue drained", "level": "info"})
+		b = msgp.AppendArrayHeader(b, 3)
+		b = msgp.AppendString(b, "worker")
+		b = msgp.AppendBytes(b, entries)
+		return b
+	})
+
+	require.Equal(t, []string{
+		`{"event":"started","fields":{"stream":"stdout"},"host":"unknown","source":"kube.var.log.containers.api","time":1735787045}`,
+		`{"event":"GET /","fields":{"stream":"stdout"},"host":"unknown","source":"kube.var.log.containers.web","time":1735787045.678}`,
+		`{"event":"GET /health","fields":{"stream":"stdout"},"host":"unknown","source":"kube.var.log.containers.web","time":1735787046.678}`,
+		`{"event":"queue drained","fields":{"level":"info"},"host":"unknown","source":"worker","time":1735787045.678}`,
+	}, testutils.CollectEvents(t, stdoutLines, 4))
+
+	stop()
+}
+
+func TestRunReceivesStatsDAndGraphite(t *testing.T) {
+	statsdPort := testutils.GetFreePort(t)
+	graphitePort := testutils.GetFreePort(t)
+	stdoutLines, stop := startRun(t, runStanza{params: fmt.Sprintf(`<param name="statsd_port">%d</param><param name="statsd_aggregation_interval">1</param><param name="statsd_percentiles">50,90</param><param name="graphite_port">%d</param>`, statsdPort, graphitePort)})
+
+	graphite, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", graphitePort))
+	require.NoError(t, err)
+	_, err = fmt.Fprintf(graphite, "disk.used;host=db-1;mount=/data 42.5 1735787045\n")
+	require.NoError(t, err)
+	require.NoError(t, graphite.Close())
+	require.Equal(t, []string{
+		`{"event":"metric","fields":{"host":"db-1","metric_name:disk.used":42.5,"metric_type":"Gauge","mount":"/data"},"host":"unknown","time":1735787045}`,
+	}, testutils.CollectEvents(t, stdoutLines, 1))
+
+	statsd, err := net.Dial("udp", fmt.Sprintf("127.0.0.1:%d", statsdPort))
+	require.NoError(t, err)
+	_, err = statsd.Write([]byte("requests:1|c|#env:prod\nrequests:2|c|#env:prod\nqueue.size:7|g\nlatency:10|ms|#env:prod\nlatency:20|ms|#env:prod\nlatency:30|ms|#env:prod"))
+	require.NoError(t, err)
+	require.NoError(t, statsd.Close())
+	// The metrics are reported at the end of the aggregation interval, in no particular order.
+	var events []string
+	for _, line := range testutils.CollectEvents(t, stdoutLines, 6) {
+		var event map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		require.NotZero(t, event["time"])
+		delete(event, "time")
+		b, marshalErr := json.Marshal(event)
+		require.NoError(t, marshalErr)
+		events = append(events, string(b))
+	}
+	require.ElementsMatch(t, []string{
+		`{"event":"metric","fields":{"metric_name:queue.size":7,"metric_type":"Gauge"},"host":"unknown"}`,
+		`{"event":"metric","fields":{"env":"prod","metric_name:requests":3,"metric_type":"Sum"},"host":"unknown"}`,
+		`{"event":"metric","fields":{"env":"prod","metric_name:latency_sum":60,"metric_type":"Summary"},"host":"unknown"}`,
+		`{"event":"metric","fields":{"env":"prod","metric_name:latency_count":3,"metric_type":"Summary"},"host":"unknown"}`,
+		`{"event":"metric","fields":{"env":"prod","metric_name:latency_0.5":20,"metric_type":"Summary","qt":"0.5"},"host":"unknown"}`,
+		`{"event":"metric","fields":{"env":"prod","metric_name:latency_0.9":30,"metric_type":"Summary","qt":"0.9"},"host":"unknown"}`,
+	}, events)
+
+	stop()
+}
+
+func TestValidateArguments(t *testing.T) {
+	restoreStdin := testutils.WriteToStdin(t, `<items><item name="test"><param name="grpc_port">4317</param><param name="http_port">4317</param></item></items>`)
+	defer restoreStdin()
+
+	err := validateArguments()
+	require.EqualError(t, err, "grpc_port and http_port must be different, both are set to 4317")
+}
+
+func TestRunRefusesInvalidPorts(t *testing.T) {
+	restoreStdin := testutils.WriteToStdin(t, `<input><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">abc</param></stanza></configuration></input>`)
+	defer restoreStdin()
+
+	err := run()
+	require.EqualError(t, err, `grpc_port "abc" is not a number`)
+}
+
+func TestRunServesMutualTLS(t *testing.T) {
+	files := testutils.WriteTLSFiles(t)
+	grpcPort := testutils.GetFreePort(t)
+	httpPort := testutils.GetFreePort(t)
+
+	config := fmt.Sprintf(`<input><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="cert_file">%s</param><param name="key_file">%s</param><param name="client_ca_file">%s</param><param name="min_version">1.3</param></stanza></configuration></input>`,
+		grpcPort, httpPort, files.CertFile, files.KeyFile, files.CAFile)
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, run)
+
+	payload, err := os.ReadFile(filepath.Join("testdata", "otlp_logs.json"))
+	require.NoError(t, err)
+	url := fmt.Sprintf("https://127.0.0.1:%d/v1/logs", httpPort)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: files.ClientTLSConfig(t, true)}}
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		resp, postErr := client.Post(url, "application/json", bytes.NewReader(payload))
+		if !assert.NoError(c, postErr) {
+			return
+		}
+		_ = resp.Body.Close()
+		assert.Equal(c, http.StatusOK, resp.StatusCode)
+	}, 5*time.Second, 100*time.Millisecond)
+	require.NotEmpty(t, testutils.CollectEvents(t, stdoutLines, 1))
+
+	noClientCert := &http.Client{Transport: &http.Transport{TLSClientConfig: files.ClientTLSConfig(t, false)}}
+	_, err = noClientCert.Post(url, "application/json", bytes.NewReader(payload))
+	require.Error(t, err)
+
+	require.NoError(t, stop())
+}
+
+func TestRunRequiresBearerToken(t *testing.T) {
+	splunkd := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Splunk session" || r.URL.Path != "/servicesNS/nobody/search/storage/passwords/splunk-connect-for-otlp:team_a:" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"entry":[{"name":"splunk-connect-for-otlp:team_a:","content":{"clear_password":"s3cr3t"}}]}`))
+	}))
+	defer splunkd.Close()
+
+	grpcPort := testutils.GetFreePort(t)
+	httpPort := testutils.GetFreePort(t)
+	config := fmt.Sprintf(`<input><server_uri>%s</server_uri><session_key>session</session_key><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="auth_tokens">team_a</param></stanza></configuration></input>`,
+		splunkd.URL, grpcPort, httpPort)
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, run)
+
+	payload, err := os.ReadFile(filepath.Join("testdata", "otlp_logs.json"))
+	require.NoError(t, err)
+	post := func(token string) int {
+		req, reqErr := http.NewRequest(http.MethodPost, fmt.Sprintf("http://127.0.0.1:%d/v1/logs", httpPort), bytes.NewReader(payload))
+		require.NoError(t, reqErr)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, postErr := http.DefaultClient.Do(req)
+		if postErr != nil {
+			return 0
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	require.Eventually(t, func() bool {
+		return post("") == http.StatusUnauthorized
+	}, 5*time.Second, 100*time.Millisecond)
+	require.Equal(t, http.StatusUnauthorized, post("wrong"))
+	require.Equal(t, http.StatusOK, post("s3cr3t"))
+	require.NotEmpty(t, testutils.CollectEvents(t, stdoutLines, 1))
+
+	require.NoError(t, stop())
+}
+
+func TestRunFailsWithoutSecret(t *testing.T) {
+	splunkd := httptest.NewTLSServer(http.NotFoundHandler())
+	defer splunkd.Close()
+
+	restoreStdin := testutils.WriteToStdin(t, fmt.Sprintf(`<input><server_uri>%s</server_uri><session_key>session</session_key><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="auth_tokens">missing</param></stanza></configuration></input>`, splunkd.URL))
+	defer restoreStdin()
+
+	err := run()
+	require.ErrorContains(t, err, `cannot read secret "missing": unexpected status 404 Not Found`)
+}
+
+func TestRunAppliesStanzaDefaults(t *testing.T) {
+	grpcPort := testutils.GetFreePort(t)
+	httpPort := testutils.GetFreePort(t)
+	config := fmt.Sprintf(`<input><server_host>splunk-hf-1</server_host><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="index">otlp</param><param name="sourcetype">_splunk-connect-for-otlp</param><param name="logs_sourcetype">otlp:test</param><param name="source">edge</param><param name="host">$decideOnStartup</param></stanza></configuration></input>`, grpcPort, httpPort)
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, run)
+
+	testutils.PostOTLP(t, httpPort, "/v1/logs", []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"hello"}}]}]}]}`))
+	require.Equal(t, []string{`{"event":"hello","host":"splunk-hf-1","source":"edge","sourcetype":"otlp:test","index":"otlp"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+
+	require.NoError(t, stop())
+}
+
+func TestRunServesStanzasInOneProcess(t *testing.T) {
+	httpPortA := testutils.GetFreePort(t)
+	httpPortB := testutils.GetFreePort(t)
+	config := fmt.Sprintf(`<input><server_host>splunk-hf-1</server_host><configuration>`+
+		`<stanza name="splunk-connect-for-otlp://team_a" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="index">team_a</param><param name="host">$decideOnStartup</param></stanza>`+
+		`<stanza name="splunk-connect-for-otlp://team_b" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="index">team_b</param><param name="logs_sourcetype">otlp:team_b</param></stanza>`+
+		`</configuration></input>`, testutils.GetFreePort(t), httpPortA, testutils.GetFreePort(t), httpPortB)
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, run)
+
+	testutils.PostOTLP(t, httpPortA, "/v1/logs", []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"from a"}}]}]}]}`))
+	require.Equal(t, []string{`{"event":"from a","host":"splunk-hf-1","source":"splunk-connect-for-otlp://team_a","index":"team_a"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+	testutils.PostOTLP(t, httpPortB, "/v1/logs", []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"from b"}}]}]}]}`))
+	require.Equal(t, []string{`{"event":"from b","host":"unknown","source":"splunk-connect-for-otlp://team_b","sourcetype":"otlp:team_b","index":"team_b"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+
+	require.NoError(t, stop())
+}
+
+func TestRunNamesComponentsAfterStanzasWithSpaces(t *testing.T) {
+	splunkd := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/nobody/search/storage/passwords/splunk-connect-for-otlp:team_a:" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"entry":[{"name":"splunk-connect-for-otlp:team_a:","content":{"clear_password":"s3cr3t"}}]}`))
+	}))
+	defer splunkd.Close()
+
+	httpPort := testutils.GetFreePort(t)
+	config := fmt.Sprintf(`<input><server_uri>%s</server_uri><session_key>session</session_key><configuration>`+
+		`<stanza name="splunk-connect-for-otlp://team a" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="auth_tokens">team_a</param><param name="index">team_a</param></stanza>`+
+		`<stanza name="splunk-connect-for-otlp://team%%20a" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="index">team_b</param></stanza>`+
+		`</configuration></input>`, splunkd.URL, testutils.GetFreePort(t), httpPort, testutils.GetFreePort(t), testutils.GetFreePort(t))
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, run)
+
+	payload := []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"from a"}}]}]}]}`)
+	post := func(token string) int {
+		req, reqErr := http.NewRequest(http.MethodPost, fmt.Sprintf("http://127.0.0.1:%d/v1/logs", httpPort), bytes.NewReader(payload))
+		require.NoError(t, reqErr)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, postErr := http.DefaultClient.Do(req)
+		if postErr != nil {
+			return 0
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	require.Eventually(t, func() bool {
+		return post("wrong") == http.StatusUnauthorized
+	}, 5*time.Second, 100*time.Millisecond)
+	require.Equal(t, http.StatusOK, post("s3cr3t"))
+	require.Equal(t, []string{`{"event":"from a","host":"unknown","source":"splunk-connect-for-otlp://team a","index":"team_a"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+
+	require.NoError(t, stop())
+}
+
+func TestRunReceivesPrometheusRemoteWrite(t *testing.T) {
+	httpPort := testutils.GetFreePort(t)
+	prwPort := testutils.GetFreePort(t)
+	stdoutLines, stop := startRun(t, runStanza{
+		params:   fmt.Sprintf(`<param name="prometheus_remote_write_port">%d</param><param name="metrics_index">metrics</param>`, prwPort),
+		httpPort: httpPort,
+	})
+
+	testutils.PostOTLP(t, httpPort, "/v1/metrics", []byte(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"up","gauge":{"dataPoints":[{"asDouble":1,"timeUnixNano":"1700000000123000000","attributes":[{"key":"job","value":{"stringValue":"node"}}]}]}}]}]}]}`))
+	otlpEvent := testutils.CollectEvents(t, stdoutLines, 1)
+
+	// A prometheus.WriteRequest with the sample up{job="node"} 1 at the same time.
+	var labels, sample, series, request []byte
+	for _, l := range [][2]string{{"__name__", "up"}, {"job", "node"}} {
+		var lb []byte
+		lb = protowire.AppendTag(lb, 1, protowire.BytesType)
+		lb = protowire.AppendString(lb, l[0])
+		lb = protowire.AppendTag(lb, 2, protowire.BytesType)
+		lb = protowire.AppendString(lb, l[1])
+		labels = protowire.AppendTag(labels, 1, protowire.BytesType)
+		labels = protowire.AppendBytes(labels, lb)
+	}
+	sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+	sample = protowire.AppendFixed64(sample, 0x3ff0000000000000)
+	sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+	sample = protowire.AppendVarint(sample, 1700000000123)
+	series = append(labels, protowire.AppendTag(nil, 2, protowire.BytesType)...)
+	series = protowire.AppendBytes(series, sample)
+	request = protowire.AppendTag(request, 1, protowire.BytesType)
+	request = protowire.AppendBytes(request, series)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://127.0.0.1:%d/api/v1/write", prwPort), bytes.NewReader(snappy.Encode(nil, request)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// Labels are dimensions of the metric event, like the attributes of OTLP data points.
+	require.Equal(t, otlpEvent, testutils.CollectEvents(t, stdoutLines, 1))
+
+	stop()
+}
+
+func TestRunRefusesMissingStanza(t *testing.T) {
+	restoreStdin := testutils.WriteToStdin(t, `<input><configuration></configuration></input>`)
+	defer restoreStdin()
+
+	require.EqualError(t, run(), "splunkd did not send any input stanza")
+}
+
+func TestRunServesHealth(t *testing.T) {
+	healthPort := testutils.GetFreePort(t)
+	config := fmt.Sprintf(`<input><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="health_port">%d</param><param name="listen_address">127.0.0.1</param><param name="telemetry_interval">60</param></stanza></configuration></input>`,
+		testutils.GetFreePort(t), testutils.GetFreePort(t), healthPort)
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+
+	stop := startInput(t, run)
+
+	var body map[string]any
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/health/ready", healthPort))
+		if !assert.NoError(c, err) {
+			return
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		assert.Equal(c, http.StatusOK, resp.StatusCode)
+		assert.NoError(c, json.NewDecoder(resp.Body).Decode(&body))
+	}, 5*time.Second, 100*time.Millisecond)
+	require.Equal(t, "ready", body["status"])
+	require.Len(t, body["components"], 6)
+
+	require.NoError(t, stop())
+}
+
+func TestRunChangesLogLevel(t *testing.T) {
+	healthPort := testutils.GetFreePort(t)
+	config := fmt.Sprintf(`<input><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="health_port">%d</param><param name="listen_address">127.0.0.1</param><param name="log_level">warn</param><param name="log_format">splunkd</param></stanza></configuration></input>`,
+		testutils.GetFreePort(t), testutils.GetFreePort(t), healthPort)
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+
+	stop := startInput(t, run)
+
+	levelURL := fmt.Sprintf("http://127.0.0.1:%d/log/level", healthPort)
+	requireLevel := func(expected string) {
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			resp, err := http.Get(levelURL)
+			if !assert.NoError(c, err) {
+				return
+			}
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			var body map[string]string
+			assert.NoError(c, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(c, expected, body["level"])
+		}, 5*time.Second, 100*time.Millisecond)
+	}
+	requireLevel("warn")
+
+	req, err := http.NewRequest(http.MethodPut, levelURL, strings.NewReader(`{"level":"error"}`))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	requireLevel("error")
+
+	require.NoError(t, stop())
+}
+
+func TestRunReloadsStanza(t *testing.T) {
+	grpcPort := testutils.GetFreePort(t)
+	httpPort := testutils.GetFreePort(t)
+	newHTTPPort := testutils.GetFreePort(t)
+	var mu sync.Mutex
+	content := fmt.Sprintf(`{"grpc_port":%d,"http_port":%d,"listen_address":"127.0.0.1","index":"otlp"}`, grpcPort, httpPort)
+	setContent := func(format string, args ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		content = fmt.Sprintf(format, args...)
+	}
+	splunkd := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Splunk session" || r.URL.Path != "/servicesNS/nobody/search/data/inputs/splunk-connect-for-otlp/test" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintf(w, `{"entry":[{"name":"test","acl":{"app":"search"},"content":%s}]}`, content)
+	}))
+	defer splunkd.Close()
+
+	config := fmt.Sprintf(`<input><server_uri>%s</server_uri><session_key>session</session_key><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="index">otlp</param></stanza></configuration></input>`,
+		splunkd.URL, grpcPort, httpPort)
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, run)
+
+	payload := []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"hello"}}]}]}]}`)
+	testutils.PostOTLP(t, httpPort, "/v1/logs", payload)
+	require.Equal(t, []string{`{"event":"hello","host":"unknown","index":"otlp"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+
+	// Changing the index replaces the exporters only: the receiver accepts every request meanwhile.
+	setContent(`{"grpc_port":%d,"http_port":%d,"listen_address":"127.0.0.1","index":"main"}`, grpcPort, httpPort)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := http.Post(fmt.Sprintf("http://127.0.0.1:%d/v1/logs", httpPort), "application/json", bytes.NewReader(payload))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		if testutils.CollectEvents(t, stdoutLines, 1)[0] == `{"event":"hello","host":"unknown","index":"main"}` {
+			break
+		}
+		require.True(t, time.Now().Before(deadline), "the index was not reloaded in time")
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// Changing the port replaces the receiver.
+	setContent(`{"grpc_port":%d,"http_port":%d,"listen_address":"127.0.0.1","index":"main"}`, grpcPort, newHTTPPort)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	testutils.PostOTLP(t, newHTTPPort, "/v1/logs", payload)
+	require.Equal(t, []string{`{"event":"hello","host":"unknown","index":"main"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+	_, err := http.Post(fmt.Sprintf("http://127.0.0.1:%d/v1/logs", httpPort), "application/json", bytes.NewReader(payload))
+	require.Error(t, err)
+
+	require.NoError(t, stop())
+}
+
+func TestServeReloadsConfigFile(t *testing.T) {
+	httpPort := testutils.GetFreePort(t)
+	grpcPort := testutils.GetFreePort(t)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(index string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(fmt.Sprintf(`
+inputs:
+  team_a:
+    grpc_port: %d
+    http_port: %d
+    listen_address: 127.0.0.1
+    index: %s
+`, grpcPort, httpPort, index)), 0o600))
+	}
+	writeConfig("team_a")
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, func() error {
+		return serve([]string{"--config", configPath})
+	})
+
+	payload := []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"from a"}}]}]}]}`)
+	testutils.PostOTLP(t, httpPort, "/v1/logs", payload)
+	require.Equal(t, []string{`{"event":"from a","host":"unknown","index":"team_a"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+
+	writeConfig("team_a_v2")
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	require.Eventually(t, func() bool {
+		testutils.PostOTLP(t, httpPort, "/v1/logs", payload)
+		return testutils.CollectEvents(t, stdoutLines, 1)[0] == `{"event":"from a","host":"unknown","index":"team_a_v2"}`
+	}, 5*time.Second, 100*time.Millisecond)
+
+	require.NoError(t, stop())
+}
+
+func TestServeRefusesReloadOnSharedPort(t *testing.T) {
+	httpPortA := testutils.GetFreePort(t)
+	httpPortB := testutils.GetFreePort(t)
+	grpcPortA := testutils.GetFreePort(t)
+	grpcPortB := testutils.GetFreePort(t)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(httpPortB int, indexB string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(fmt.Sprintf(`
+inputs:
+  team_a:
+    grpc_port: %d
+    http_port: %d
+    listen_address: 127.0.0.1
+    index: team_a
+  team_b:
+    grpc_port: %d
+    http_port: %d
+    listen_address: 127.0.0.1
+    index: %s
+`, grpcPortA, httpPortA, grpcPortB, httpPortB, indexB)), 0o600))
+	}
+	writeConfig(httpPortB, "team_b")
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, func() error {
+		return serve([]string{"--config", configPath})
+	})
+
+	payload := []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"from b"}}]}]}]}`)
+	testutils.PostOTLP(t, httpPortB, "/v1/logs", payload)
+	require.Equal(t, []string{`{"event":"from b","host":"unknown","index":"team_b"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+
+	// team_b would listen on the port of team_a, so none of the changes apply.
+	writeConfig(httpPortA, "team_b_v2")
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	time.Sleep(500 * time.Millisecond)
+	testutils.PostOTLP(t, httpPortB, "/v1/logs", payload)
+	require.Equal(t, []string{`{"event":"from b","host":"unknown","index":"team_b"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+
+	require.NoError(t, stop())
+}
+
+func TestServeReloadsRotatedToken(t *testing.T) {
+	httpPort := testutils.GetFreePort(t)
+	grpcPort := testutils.GetFreePort(t)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(token string) {
+		require.NoError(t, os.WriteFile(configPath, []byte(fmt.Sprintf(`
+secrets:
+  team_a: %s
+inputs:
+  team_a:
+    grpc_port: %d
+    http_port: %d
+    listen_address: 127.0.0.1
+    auth_tokens: team_a
+`, token, grpcPort, httpPort)), 0o600))
+	}
+	writeConfig("old-token")
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, func() error {
+		return serve([]string{"--config", configPath})
+	})
+
+	payload := []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"from a"}}]}]}]}`)
+	post := func(token string) int {
+		req, reqErr := http.NewRequest(http.MethodPost, fmt.Sprintf("http://127.0.0.1:%d/v1/logs", httpPort), bytes.NewReader(payload))
+		require.NoError(t, reqErr)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, postErr := http.DefaultClient.Do(req)
+		if postErr != nil {
+			return 0
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	require.Eventually(t, func() bool {
+		return post("old-token") == http.StatusOK
+	}, 5*time.Second, 100*time.Millisecond)
+	testutils.CollectEvents(t, stdoutLines, 1)
+
+	// The secret keeps its name, only its value changes.
+	writeConfig("new-token")
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	require.Eventually(t, func() bool {
+		return post("new-token") == http.StatusOK
+	}, 5*time.Second, 100*time.Millisecond)
+	testutils.CollectEvents(t, stdoutLines, 1)
+	require.Equal(t, http.StatusUnauthorized, post("old-token"))
+
+	require.NoError(t, stop())
+}
+
+func TestRunExportsSelfTelemetry(t *testing.T) {
+	httpPort := testutils.GetFreePort(t)
+	config := fmt.Sprintf(`<input><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="telemetry_index">otlp_internal</param><param name="telemetry_interval">1</param></stanza></configuration></input>`,
+		testutils.GetFreePort(t), httpPort)
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, run)
+
+	testutils.PostOTLP(t, httpPort, "/v1/logs", []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"hello"}}]}]}]}`))
+
+	var accepted map[string]any
+	timeout := time.After(5 * time.Second)
+	for accepted == nil {
+		select {
+		case line := <-stdoutLines:
+			var event map[string]any
+			require.NoError(t, json.Unmarshal([]byte(testutils.HECEventFromXML(t, line)), &event))
+			fields, _ := event["fields"].(map[string]any)
+			if _, ok := fields["metric_name:otelcol_receiver_accepted_log_records"]; ok && event["index"] == "otlp_internal" {
+				accepted = event
+			}
+		case <-timeout:
+			t.Fatal("self telemetry was not exported in time")
+		}
+	}
+	require.Equal(t, "splunk-connect-for-otlp://test", accepted["source"])
+	require.Equal(t, map[string]any{
+		"metric_name:otelcol_receiver_accepted_log_records": float64(1),
+		"metric_type":  "Sum",
+		"receiver":     "otlp",
+		"service.name": "splunk-connect-for-otlp",
+		"transport":    "http",
+	}, accepted["fields"])
+
+	require.NoError(t, stop())
+}
+
+func TestRunPersistsQueueInCheckpointDir(t *testing.T) {
+	checkpointDir := t.TempDir()
+	httpPort := testutils.GetFreePort(t)
+	config := fmt.Sprintf(`<input><checkpoint_dir>%s</checkpoint_dir><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="persistent_queue">true</param></stanza></configuration></input>`,
+		checkpointDir, testutils.GetFreePort(t), httpPort)
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, run)
+
+	testutils.PostOTLP(t, httpPort, "/v1/logs", []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"hello"}}]}]}]}`))
+	require.Equal(t, []string{`{"event":"hello","host":"unknown"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+
+	require.NoError(t, stop())
+
+	queueFiles, err := os.ReadDir(filepath.Join(checkpointDir, "queue", "test"))
+	require.NoError(t, err)
+	require.NotEmpty(t, queueFiles)
+}
+
+func TestRunDeliversQueueAfterRestart(t *testing.T) {
+	splunkd := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"entry":[{"name":"splunk-connect-for-otlp:edge:","content":{"clear_password":"hec-token"}}]}`))
+	}))
+	defer splunkd.Close()
+
+	// HEC is down until the input restarts, so the event stays in the queue.
+	var available atomic.Bool
+	attempts := make(chan struct{}, 100)
+	hecLines := make(chan string, 10)
+	hec := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available.Load() {
+			select {
+			case attempts <- struct{}{}:
+			default:
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, gzErr := gzip.NewReader(r.Body)
+		if !assert.NoError(t, gzErr) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			hecLines <- scanner.Text()
+		}
+		_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	// HEC is closed once the input has stopped, as the input may still send to it when a check fails.
+	t.Cleanup(hec.Close)
+
+	checkpointDir := t.TempDir()
+	httpPort := testutils.GetFreePort(t)
+	config := fmt.Sprintf(`<input><server_uri>%s</server_uri><session_key>session</session_key><checkpoint_dir>%s</checkpoint_dir><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="output_mode">hec</param><param name="hec_endpoint">%s</param><param name="hec_token">edge</param><param name="persistent_queue">true</param><param name="shutdown_timeout">1</param><param name="telemetry_interval">0</param></stanza></configuration></input>`,
+		splunkd.URL, checkpointDir, testutils.GetFreePort(t), httpPort, hec.URL)
+	start := func() func() error {
+		restoreStdin := testutils.WriteToStdin(t, config)
+		t.Cleanup(restoreStdin)
+		return startInput(t, run)
+	}
+
+	stop := start()
+	testutils.PostOTLP(t, httpPort, "/v1/logs", []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"queued"}}]}]}]}`))
+	select {
+	case <-attempts:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event was not sent to HEC")
+	}
+	// The queue cannot drain while HEC is down, so the shutdown may report an error.
+	_ = stop()
+	require.Empty(t, hecLines)
+
+	available.Store(true)
+	stop = start()
+	require.Equal(t, []string{`{"event":"queued","host":"unknown"}`}, testutils.CollectLines(t, hecLines, 1))
+
+	require.NoError(t, stop())
+}
+
+func TestExpectedXMLStream(t *testing.T) {
+	for _, signal := range []string{"logs", "metrics", "traces"} {
+		t.Run(signal, func(t *testing.T) {
+			payload, err := os.ReadFile(filepath.Join("testdata", "otlp_"+signal+".json"))
+			require.NoError(t, err)
+			expected := testutils.LoadExpectedHecData(t, filepath.Join("testdata", "expected_xml_"+signal+".xml"))
 			expectedLines := strings.Split(strings.TrimSpace(string(expected)), "\n")
-			require.NotEmpty(t, expectedLines, "%s must contain fixture data", tt.expectedPath)
 
-			testutils.PostOTLP(t, httpPort, tt.otlpendpoint, payload)
+			httpPort := testutils.GetFreePort(t)
+			stdoutLines, stop := startRun(t, runStanza{httpPort: httpPort})
 
-			actual := testutils.CollectLines(t, stdoutLines, len(expectedLines))
-			require.Equal(t, expectedLines, actual)
+			// splunkd reads the events in the xml streaming mode of the scheme.
+			testutils.PostOTLP(t, httpPort, "/v1/"+signal, payload)
+			require.Equal(t, expectedLines, testutils.CollectLines(t, stdoutLines, len(expectedLines)))
 
-			require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
-			require.NoError(t, <-runDone)
+			stop()
 		})
 	}
 }
+
+func TestRunForwardsToHEC(t *testing.T) {
+	payload, err := os.ReadFile(filepath.Join("testdata", "otlp_logs.json"))
+	require.NoError(t, err)
+	expected := testutils.LoadExpectedHecData(t, filepath.Join("testdata", "expected_hec_logs.json"))
+	expectedLines := strings.Split(strings.TrimSpace(string(expected)), "\n")
+
+	splunkd := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/nobody/search/storage/passwords/splunk-connect-for-otlp:edge:" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"entry":[{"name":"splunk-connect-for-otlp:edge:","content":{"clear_password":"hec-token"}}]}`))
+	}))
+	defer splunkd.Close()
+
+	hecLines := make(chan string, len(expectedLines))
+	hec := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Splunk hec-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, gzErr := gzip.NewReader(r.Body)
+		if !assert.NoError(t, gzErr) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path == "/services/collector/ack" {
+			_, _ = w.Write([]byte(`{"acks":{"0":true}}`))
+			return
+		}
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			hecLines <- scanner.Text()
+		}
+		_, _ = w.Write([]byte(`{"text":"Success","code":0,"ackId":0}`))
+	}))
+	defer hec.Close()
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	httpPort := testutils.GetFreePort(t)
+	config := fmt.Sprintf(`<input><server_uri>%s</server_uri><session_key>session</session_key><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="output_mode">hec</param><param name="hec_endpoint">%s</param><param name="hec_token">edge</param><param name="hec_use_ack">1</param></stanza></configuration></input>`,
+		splunkd.URL, testutils.GetFreePort(t), httpPort, hec.URL)
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+
+	stop := startInput(t, run)
+
+	testutils.PostOTLP(t, httpPort, "/v1/logs", payload)
+	require.Equal(t, expectedLines, testutils.CollectLines(t, hecLines, len(expectedLines)))
+
+	require.NoError(t, stop())
+
+	select {
+	case line := <-stdoutLines:
+		t.Fatalf("no event must be written to stdout in hec output mode, got %s", line)
+	default:
+	}
+}
+
+func TestRunShutdownTimeout(t *testing.T) {
+	splunkd := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"entry":[{"name":"splunk-connect-for-otlp:edge:","content":{"clear_password":"hec-token"}}]}`))
+	}))
+	defer splunkd.Close()
+
+	// HEC never responds, so the exporter queues cannot drain.
+	release := make(chan struct{})
+	received := make(chan struct{}, 2)
+	hec := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+		<-release
+	}))
+	defer hec.Close()
+	defer close(release)
+
+	httpPort := testutils.GetFreePort(t)
+	config := fmt.Sprintf(`<input><server_uri>%s</server_uri><session_key>session</session_key><configuration><stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param><param name="output_mode">hec</param><param name="hec_endpoint">%s</param><param name="hec_token">edge</param><param name="shutdown_timeout">1</param></stanza></configuration></input>`,
+		splunkd.URL, testutils.GetFreePort(t), httpPort, hec.URL)
+	restoreStdin := testutils.WriteToStdin(t, config)
+	t.Cleanup(restoreStdin)
+	stderr, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	require.NoError(t, err)
+	originalStderr := os.Stderr
+	os.Stderr = stderr
+	t.Cleanup(func() {
+		os.Stderr = originalStderr
+	})
+
+	stop := startInput(t, run)
+
+	testutils.PostOTLP(t, httpPort, "/v1/logs", []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"hello"}},{"body":{"stringValue":"world"}}]}]}]}`))
+	testutils.PostOTLP(t, httpPort, "/v1/traces", []byte(`{"resourceSpans":[{"scopeSpans":[{"spans":[{"traceId":"5982fe77008310cc80f1da5e10147517","spanId":"bcd1a4a5ecd3c2a5","name":"get"}]}]}]}`))
+	<-received
+	<-received
+
+	stopped := time.Now()
+	err = stop()
+	require.ErrorContains(t, err, "cannot shut hec/logs down: context deadline exceeded")
+	require.Less(t, time.Since(stopped), 5*time.Second)
+
+	// The items the receiver accepted are reported as discarded, per signal.
+	logs, err := os.ReadFile(stderr.Name())
+	require.NoError(t, err)
+	var warning map[string]any
+	for _, line := range strings.Split(string(logs), "\n") {
+		if strings.Contains(line, "Shutdown timed out") {
+			require.NoError(t, json.Unmarshal([]byte(line), &warning))
+		}
+	}
+	require.NotNil(t, warning, "the shutdown timeout was not logged")
+	require.Equal(t, "Shutdown timed out, dropping the requests left in the exporter queues", warning["msg"])
+	require.InDelta(t, 0, warning["flushed"], 0)
+	require.InDelta(t, 2, warning["discarded_log_records"], 0)
+	require.InDelta(t, 1, warning["discarded_spans"], 0)
+	require.InDelta(t, 0, warning["discarded_metric_points"], 0)
+}
+
+func TestServeConfigFile(t *testing.T) {
+	httpPortA := testutils.GetFreePort(t)
+	httpPortB := testutils.GetFreePort(t)
+	t.Setenv("OTLP_TEAM_B_INDEX", "team_b")
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(fmt.Sprintf(`
+host: edge-1
+inputs:
+  team_a:
+    grpc_port: %d
+    http_port: %d
+    listen_address: 127.0.0.1
+    host: $decideOnStartup
+    index: team_a
+  team_b:
+    grpc_port: %d
+    http_port: %d
+    listen_address: 127.0.0.1
+    index: ${OTLP_TEAM_B_INDEX}
+    logs_sourcetype: otlp:team_b
+`, testutils.GetFreePort(t), httpPortA, testutils.GetFreePort(t), httpPortB)), 0o600))
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, func() error {
+		return serve([]string{"--config", configPath})
+	})
+
+	testutils.PostOTLP(t, httpPortA, "/v1/logs", []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"from a"}}]}]}]}`))
+	require.Equal(t, []string{`{"event":"from a","host":"edge-1","index":"team_a"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+	testutils.PostOTLP(t, httpPortB, "/v1/logs", []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"from b"}}]}]}]}`))
+	require.Equal(t, []string{`{"event":"from b","host":"unknown","sourcetype":"otlp:team_b","index":"team_b"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+
+	require.NoError(t, stop())
+}
+
+func TestServeSkipsFailingInputs(t *testing.T) {
+	httpPort := testutils.GetFreePort(t)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(fmt.Sprintf(`
+inputs:
+  team_a:
+    grpc_port: %d
+    http_port: %d
+    listen_address: 127.0.0.1
+    index: team_a
+  team_b:
+    grpc_port: %d
+    http_port: %d
+    listen_address: 127.0.0.1
+    index: team_b
+  team_c:
+    grpc_port: %d
+    http_port: %d
+    listen_address: 127.0.0.1
+    output_mode: file
+`, testutils.GetFreePort(t), httpPort, testutils.GetFreePort(t), httpPort, testutils.GetFreePort(t), testutils.GetFreePort(t))), 0o600))
+
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	t.Cleanup(restoreStdout)
+
+	stop := startInput(t, func() error {
+		return serve([]string{"--config", configPath})
+	})
+
+	testutils.PostOTLP(t, httpPort, "/v1/logs", []byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"from a"}}]}]}]}`))
+	require.Equal(t, []string{`{"event":"from a","host":"unknown","index":"team_a"}`}, testutils.CollectEvents(t, stdoutLines, 1))
+
+	require.NoError(t, stop())
+}
+
+func TestServeFailsWithoutInput(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+inputs:
+  team_a:
+    output_mode: file
+  team_b:
+    output_mode: hec
+`), 0o600))
+
+	require.EqualError(t, serve([]string{"--config", configPath}),
+		"output_mode \"file\" is not supported, it must be either stdout or hec\noutput_mode hec requires hec_endpoint\noutput_mode hec requires hec_token")
+	require.EqualError(t, serve(nil), "serve requires --config")
+}
@@ -0,0 +1,921 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/filestorage"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/fluentforwardreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/jaegerreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/syslogreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/zipkinreceiver"
+	"github.com/splunk/otlp2splunk/internal"
+	"github.com/splunk/otlp2splunk/internal/exporter/hecexporter"
+	"github.com/splunk/otlp2splunk/internal/exporter/splunkevent"
+	"github.com/splunk/otlp2splunk/internal/exporter/stdoutexporter"
+	"github.com/splunk/otlp2splunk/internal/extension/tokenauthextension"
+	"github.com/splunk/otlp2splunk/internal/receiver/prometheusremotewritereceiver"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver"
+	noopmetric "go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+)
+
+var (
+	authExtensionType = component.MustNewType("tokenauth")
+	storageType       = component.MustNewType("file_storage")
+	receiverType      = component.MustNewType("otlp")
+	telemetryType     = component.MustNewType("telemetry")
+	captureType       = component.MustNewType("capture")
+)
+
+// fluentTagAttribute is the attribute the Fluent Forward receiver sets to the tag of the records.
+const fluentTagAttribute = "fluent.tag"
+
+// pipeline holds the components receiving and exporting the data of an input.
+type pipeline struct {
+	settings internal.Settings
+	// secrets holds the values of the secrets the components of the pipeline were created with.
+	secrets  secrets
+	logger   *zap.Logger
+	logLevel zap.AtomicLevel
+	// input names the input the pipeline belongs to, when several inputs share the process.
+	input string
+	// componentSettings are the telemetry settings the components of the pipeline are created with.
+	componentSettings component.TelemetrySettings
+
+	extensions map[component.ID]component.Component
+	// authID checks the bearer tokens of the requests, and hecAuthID the tokens HEC clients present with the
+	// Splunk scheme.
+	authID    component.ID
+	hecAuthID component.ID
+	// auths holds the extensions checking the tokens of the requests. It is empty when auth_tokens is not set.
+	auths map[component.ID]extension.Extension
+	// receiverID is the ID of the OTLP receiver. The receivers of the pipeline are the OTLP receiver and the
+	// optional receivers of other protocols, which are replaced together.
+	receiverID component.ID
+	receivers  map[component.ID]component.Component
+	// logs, metrics and traces pass the data the receivers accept to the current exporters, through the capture
+	// when it is enabled.
+	logs    consumer.Logs
+	metrics consumer.Metrics
+	traces  consumer.Traces
+	// exportersMu guards exporters, which are replaced when the settings of the events change.
+	exportersMu sync.RWMutex
+	exporters   *exporterSet
+	telemetryID component.ID
+	telemetry   *internal.SelfTelemetry
+	// stats reads the metrics of the exporters, to report what they flushed and dropped when the pipeline stops.
+	stats *internal.ExporterStats
+	// meterProvider records the metrics of the components when self telemetry is disabled.
+	meterProvider *sdkmetric.MeterProvider
+	captureID     component.ID
+	capture       *internal.Capture
+	health        *internal.HealthServer
+}
+
+// secrets holds the values of the secrets named by the settings of a pipeline.
+type secrets struct {
+	// authTokens are the tokens the receivers accept, none when auth_tokens is not set.
+	authTokens []string
+	// hecToken is the token the exporters send to HEC, empty when the output mode is not hec.
+	hecToken string
+}
+
+// fetchSecrets reads the values of the secrets named by settings, from Splunk or from the configuration file of
+// config.
+func fetchSecrets(ctx context.Context, config internal.XMLInput, settings internal.Settings) (secrets, error) {
+	var s secrets
+	app := config.Configuration.Stanza.App
+	if len(settings.AuthTokens) > 0 {
+		tokens, err := config.FetchSecrets(ctx, app, settings.AuthTokens)
+		if err != nil {
+			return s, fmt.Errorf("cannot read authentication tokens: %w", err)
+		}
+		s.authTokens = tokens
+	}
+	if settings.OutputMode == "hec" {
+		tokens, err := config.FetchSecrets(ctx, app, []string{settings.HEC.Token})
+		if err != nil {
+			return s, fmt.Errorf("cannot read the HEC token: %w", err)
+		}
+		s.hecToken = tokens[0]
+	}
+	return s, nil
+}
+
+// exporterSet holds the exporters of a pipeline, which are replaced together.
+type exporterSet struct {
+	logsID    component.ID
+	logs      exporter.Logs
+	metricsID component.ID
+	metrics   exporter.Metrics
+	tracesID  component.ID
+	traces    exporter.Traces
+	// telemetry sends the self telemetry of the input to the telemetry index. It is nil when self telemetry is
+	// disabled.
+	telemetryID component.ID
+	telemetry   exporter.Metrics
+}
+
+// each calls fn with every exporter of the set, stopping at the first error.
+func (e *exporterSet) each(fn func(id component.ID, c component.Component) error) error {
+	if e.telemetry != nil {
+		if err := fn(e.telemetryID, e.telemetry); err != nil {
+			return err
+		}
+	}
+	if err := fn(e.logsID, e.logs); err != nil {
+		return err
+	}
+	if err := fn(e.metricsID, e.metrics); err != nil {
+		return err
+	}
+	return fn(e.tracesID, e.traces)
+}
+
+// newPipeline creates the components of the input configured by config, and the logger of the input, which
+// tags every line with the stanza name. Components are named after input when it is not empty, so the components
+// of several inputs can be told apart.
+func newPipeline(ctx context.Context, config internal.XMLInput, input string) (*pipeline, error) {
+	// Invalid parameters leave the log settings to their defaults, so the error is logged.
+	inputSettings, err := config.Extract()
+	logger, logLevel, logErr := internal.CreateLogger(inputSettings.Log)
+	if logErr != nil {
+		return nil, logErr
+	}
+	logger = logger.With(zap.String("stanza", config.Configuration.Stanza.Name))
+	logger.Info("Starting OTLP input")
+	if err != nil {
+		logger.Error("Refusing to start OTLP input, the input configuration is invalid", zap.Error(err))
+		return nil, err
+	}
+	if unauthenticated := inputSettings.UnauthenticatedReceivers(); len(inputSettings.AuthTokens) > 0 && len(unauthenticated) > 0 {
+		logger.Warn("Receivers accept data from any client, auth_tokens does not apply to them", zap.Strings("receivers", unauthenticated))
+	}
+	p := &pipeline{
+		settings:   inputSettings,
+		logger:     logger,
+		logLevel:   logLevel,
+		input:      input,
+		extensions: map[component.ID]component.Component{},
+		componentSettings: component.TelemetrySettings{
+			Logger:         logger,
+			TracerProvider: noop.NewTracerProvider(),
+			MeterProvider:  noopmetric.NewMeterProvider(),
+			Resource:       pcommon.NewResource(),
+		},
+	}
+	p.telemetryID = p.id(telemetryType, "")
+	p.receiverID = p.id(receiverType, "")
+	p.captureID = p.id(captureType, "")
+	p.authID = p.id(authExtensionType, "")
+	p.hecAuthID = p.id(authExtensionType, "hec")
+
+	// The receivers and the self telemetry pass their data to the current exporters, so the exporters can be
+	// replaced without them.
+	p.logs, _ = consumer.NewLogs(p.consumeLogs)
+	p.metrics, _ = consumer.NewMetrics(p.consumeMetrics)
+	p.traces, _ = consumer.NewTraces(p.consumeTraces)
+
+	p.stats = internal.NewExporterStats()
+	if inputSettings.Telemetry.Interval == 0 {
+		p.meterProvider = internal.NewMeterProvider(p.stats.Reader())
+		p.componentSettings.MeterProvider = p.meterProvider
+	}
+	if inputSettings.Telemetry.Interval > 0 {
+		telemetryMetrics, _ := consumer.NewMetrics(p.consumeTelemetry)
+		p.telemetry = internal.NewSelfTelemetry(logger, inputSettings.Telemetry.Interval,
+			telemetryResource(inputSettings.Name), telemetryMetrics, p.stats.Reader())
+		p.componentSettings.MeterProvider = p.telemetry.MeterProvider()
+		logger.Info("Configured self telemetry", zap.String("index", inputSettings.Telemetry.Index),
+			zap.Duration("interval", inputSettings.Telemetry.Interval))
+	}
+
+	if p.secrets, err = fetchSecrets(ctx, config, inputSettings); err != nil {
+		logger.Error("Refusing to start OTLP input, cannot read its secrets", zap.Error(err))
+		return nil, err
+	}
+
+	if p.exporters, err = p.createExporters(ctx, inputSettings, p.secrets.hecToken); err != nil {
+		logger.Error("Refusing to start OTLP input, cannot create the exporters", zap.Error(err))
+		return nil, err
+	}
+
+	if inputSettings.PersistentQueue {
+		storage, err := p.createStorage(ctx)
+		if err != nil {
+			logger.Error("Refusing to start OTLP input, cannot create the persistent queue", zap.Error(err))
+			return nil, err
+		}
+		p.extensions[p.id(storageType, "")] = storage
+		logger.Info("Configured persistent queue", zap.String("directory", inputSettings.QueueDirectory()))
+	}
+
+	if inputSettings.Capture.Enabled {
+		p.capture = internal.NewCapture(logger, inputSettings.CaptureDirectory(),
+			int64(inputSettings.Capture.MaxSize)<<20, inputSettings.Capture.MaxFiles)
+		p.logs = p.capture.Logs(p.logs)
+		p.metrics = p.capture.Metrics(p.metrics)
+		p.traces = p.capture.Traces(p.traces)
+		logger.Info("Configured request capture", zap.String("directory", inputSettings.CaptureDirectory()))
+	}
+
+	if p.receivers, p.auths, err = p.createReceivers(ctx, inputSettings, p.secrets.authTokens); err != nil {
+		logger.Error("Refusing to start OTLP input, cannot create the receivers", zap.Error(err))
+		return nil, err
+	}
+	return p, nil
+}
+
+// createExporters returns the exporters of the events of the pipeline configured with settings, sending hecToken
+// in hec output mode.
+func (p *pipeline) createExporters(ctx context.Context, settings internal.Settings, hecToken string) (*exporterSet, error) {
+	f := stdoutexporter.NewFactory()
+	newExporterConfig := func(signal internal.SignalSettings) (component.Config, error) {
+		return p.exporterConfig(settings, signal), nil
+	}
+	if settings.OutputMode == "hec" {
+		f = hecexporter.NewFactory()
+		newExporterConfig = func(signal internal.SignalSettings) (component.Config, error) {
+			cfg := p.hecExporterConfig(settings, signal, hecToken)
+			return cfg, cfg.Validate()
+		}
+	}
+	e := &exporterSet{
+		logsID:      p.id(f.Type(), "logs"),
+		metricsID:   p.id(f.Type(), "metrics"),
+		tracesID:    p.id(f.Type(), "traces"),
+		telemetryID: p.id(f.Type(), "telemetry"),
+	}
+
+	if settings.Telemetry.Interval > 0 {
+		cfg, err := newExporterConfig(internal.SignalSettings{Index: settings.Telemetry.Index})
+		if err != nil {
+			return nil, err
+		}
+		// The telemetry exporter does not record metrics itself, so self telemetry does not report on itself.
+		telemetrySettings := p.componentSettings
+		telemetrySettings.MeterProvider = noopmetric.NewMeterProvider()
+		e.telemetry, err = f.CreateMetrics(ctx, exporter.Settings{
+			TelemetrySettings: telemetrySettings,
+			ID:                e.telemetryID,
+		}, cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	logsConfig, err := newExporterConfig(settings.Logs)
+	if err != nil {
+		return nil, err
+	}
+	e.logs, err = f.CreateLogs(ctx, exporter.Settings{
+		TelemetrySettings: p.componentSettings,
+		ID:                e.logsID,
+	}, logsConfig)
+	if err != nil {
+		return nil, err
+	}
+	metricsConfig, err := newExporterConfig(settings.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	e.metrics, err = f.CreateMetrics(ctx, exporter.Settings{
+		TelemetrySettings: p.componentSettings,
+		ID:                e.metricsID,
+	}, metricsConfig)
+	if err != nil {
+		return nil, err
+	}
+	tracesConfig, err := newExporterConfig(settings.Traces)
+	if err != nil {
+		return nil, err
+	}
+	e.traces, err = f.CreateTraces(ctx, exporter.Settings{
+		TelemetrySettings: p.componentSettings,
+		ID:                e.tracesID,
+	}, tracesConfig)
+	if err != nil {
+		return nil, err
+	}
+	p.logger.Info("Configured exporter", zap.String("output_mode", settings.OutputMode))
+	return e, nil
+}
+
+// createReceivers returns the receivers of the pipeline configured with settings, and the extensions checking
+// their requests carry one of tokens, none when auth_tokens is not set.
+func (p *pipeline) createReceivers(ctx context.Context, settings internal.Settings, tokens []string) (map[component.ID]component.Component, map[component.ID]extension.Extension, error) {
+	auths := map[component.ID]extension.Extension{}
+	if len(settings.AuthTokens) > 0 {
+		schemes := map[component.ID]string{p.authID: "Bearer"}
+		if settings.HECPort != 0 {
+			schemes[p.hecAuthID] = "Splunk"
+		}
+		for id, scheme := range schemes {
+			authCfg := &tokenauthextension.Config{Scheme: scheme}
+			for _, token := range tokens {
+				authCfg.Tokens = append(authCfg.Tokens, configopaque.String(token))
+			}
+			auth, err := tokenauthextension.NewFactory().Create(ctx, extension.Settings{
+				TelemetrySettings: p.componentSettings,
+				ID:                id,
+			}, authCfg)
+			if err != nil {
+				return nil, nil, err
+			}
+			auths[id] = auth
+		}
+		p.logger.Info("Configured token authentication", zap.Int("tokens", len(tokens)))
+	}
+
+	rf := otlpreceiver.NewFactory()
+	cfg := rf.CreateDefaultConfig().(*otlpreceiver.Config)
+	if err := cfg.GRPC.Unmarshal(confmap.NewFromStringMap(p.serverConfig(settings, settings.GRPCPort))); err != nil {
+		return nil, nil, err
+	}
+	if err := cfg.HTTP.Unmarshal(confmap.NewFromStringMap(p.serverConfig(settings, settings.HTTPPort))); err != nil {
+		return nil, nil, err
+	}
+	if settings.TLS.Enabled() {
+		p.logger.Info("TLS enabled on the receivers", zap.Bool("mtls", settings.TLS.MutualTLS()))
+	}
+	receivers := map[component.ID]component.Component{}
+
+	if _, err := rf.CreateLogs(ctx, receiver.Settings{
+		TelemetrySettings: p.componentSettings,
+		ID:                p.receiverID,
+	}, cfg, p.logs); err != nil {
+		return nil, nil, err
+	}
+	if _, err := rf.CreateMetrics(ctx, receiver.Settings{
+		TelemetrySettings: p.componentSettings,
+		ID:                p.receiverID,
+	}, cfg, p.metrics); err != nil {
+		return nil, nil, err
+	}
+	r, err := rf.CreateTraces(ctx, receiver.Settings{
+		TelemetrySettings: p.componentSettings,
+		ID:                p.receiverID,
+	}, cfg, p.traces)
+	if err != nil {
+		return nil, nil, err
+	}
+	receivers[p.receiverID] = r
+	p.logger.Info("Configured OTLP receiver")
+
+	if settings.PrometheusRemoteWritePort != 0 {
+		f := prometheusremotewritereceiver.NewFactory()
+		id := p.id(f.Type(), "")
+		cfg, err := receiverConfig(f, p.serverConfig(settings, settings.PrometheusRemoteWritePort))
+		if err != nil {
+			return nil, nil, err
+		}
+		if receivers[id], err = f.CreateMetrics(ctx, receiver.Settings{
+			TelemetrySettings: p.componentSettings,
+			ID:                id,
+		}, cfg, p.metrics); err != nil {
+			return nil, nil, err
+		}
+		p.logger.Info("Configured Prometheus remote write receiver", zap.Int("port", settings.PrometheusRemoteWritePort))
+	}
+
+	if settings.ZipkinPort != 0 {
+		f := zipkinreceiver.NewFactory()
+		id := p.id(f.Type(), "")
+		cfg, err := receiverConfig(f, p.serverConfig(settings, settings.ZipkinPort))
+		if err != nil {
+			return nil, nil, err
+		}
+		if receivers[id], err = f.CreateTraces(ctx, receiver.Settings{
+			TelemetrySettings: p.componentSettings,
+			ID:                id,
+		}, cfg, p.traces); err != nil {
+			return nil, nil, err
+		}
+		p.logger.Info("Configured Zipkin receiver", zap.Int("port", settings.ZipkinPort))
+	}
+
+	if settings.JaegerGRPCPort != 0 || settings.JaegerHTTPPort != 0 {
+		// Only the protocols listed are enabled.
+		protocols := map[string]any{}
+		if settings.JaegerGRPCPort != 0 {
+			protocols["grpc"] = p.serverConfig(settings, settings.JaegerGRPCPort)
+		}
+		if settings.JaegerHTTPPort != 0 {
+			protocols["thrift_http"] = p.serverConfig(settings, settings.JaegerHTTPPort)
+		}
+		f := jaegerreceiver.NewFactory()
+		id := p.id(f.Type(), "")
+		cfg, err := receiverConfig(f, map[string]any{"protocols": protocols})
+		if err != nil {
+			return nil, nil, err
+		}
+		if receivers[id], err = f.CreateTraces(ctx, receiver.Settings{
+			TelemetrySettings: p.componentSettings,
+			ID:                id,
+		}, cfg, p.traces); err != nil {
+			return nil, nil, err
+		}
+		p.logger.Info("Configured Jaeger receiver", zap.Int("grpc_port", settings.JaegerGRPCPort),
+			zap.Int("http_port", settings.JaegerHTTPPort))
+	}
+
+	if settings.HECPort != 0 {
+		conf := p.serverConfig(settings, settings.HECPort)
+		if len(settings.AuthTokens) > 0 {
+			// HEC clients present their token with the Splunk scheme.
+			conf["auth"] = map[string]any{"authenticator": p.hecAuthID.String()}
+		}
+		f := splunkhecreceiver.NewFactory()
+		id := p.id(f.Type(), "")
+		cfg, err := receiverConfig(f, conf)
+		if err != nil {
+			return nil, nil, err
+		}
+		// The logs and metrics receivers share the server, they are the same component.
+		if _, err = f.CreateLogs(ctx, receiver.Settings{
+			TelemetrySettings: p.componentSettings,
+			ID:                id,
+		}, cfg, p.logs); err != nil {
+			return nil, nil, err
+		}
+		if receivers[id], err = f.CreateMetrics(ctx, receiver.Settings{
+			TelemetrySettings: p.componentSettings,
+			ID:                id,
+		}, cfg, p.metrics); err != nil {
+			return nil, nil, err
+		}
+		p.logger.Info("Configured HEC receiver", zap.Int("port", settings.HECPort))
+	}
+
+	// A syslog receiver listens on a single network, so each network gets its own receiver.
+	for network, port := range map[string]int{"tcp": settings.Syslog.TCPPort, "udp": settings.Syslog.UDPPort} {
+		if port == 0 {
+			continue
+		}
+		conf := map[string]any{"listen_address": net.JoinHostPort(settings.ListenAddress, strconv.Itoa(port))}
+		if network == "tcp" && settings.TLS.Enabled() {
+			conf["tls"] = tlsConfig(settings)
+		}
+		syslogConf := map[string]any{"protocol": settings.Syslog.Protocol, network: conf}
+		var operators []any
+		if network == "tcp" && settings.Syslog.OctetCounting {
+			syslogConf["enable_octet_counting"] = true
+			// The body is the frame, strip its length so the event is the message as sent.
+			operators = append(operators,
+				map[string]any{"type": "regex_parser", "regex": `^\d+ (?s)(?P<message>.*)$`, "parse_from": "body", "parse_to": "body"},
+				map[string]any{"type": "move", "from": "body.message", "to": "body"})
+		}
+		// The host of the event is the host which sent the message.
+		syslogConf["operators"] = append(operators, map[string]any{
+			"type": "copy", "from": "attributes.hostname", "to": `resource["host.name"]`, "if": "attributes.hostname != nil",
+		})
+		f := syslogreceiver.NewFactory()
+		id := p.id(f.Type(), network)
+		cfg, err := receiverConfig(f, syslogConf)
+		if err != nil {
+			return nil, nil, err
+		}
+		if receivers[id], err = f.CreateLogs(ctx, receiver.Settings{
+			TelemetrySettings: p.componentSettings,
+			ID:                id,
+		}, cfg, p.logs); err != nil {
+			return nil, nil, err
+		}
+		p.logger.Info("Configured syslog receiver", zap.String("network", network), zap.Int("port", port),
+			zap.String("protocol", settings.Syslog.Protocol))
+	}
+
+	if settings.FluentForward.Port != 0 {
+		f := fluentforwardreceiver.NewFactory()
+		id := p.id(f.Type(), "")
+		cfg, err := receiverConfig(f, map[string]any{
+			"endpoint": net.JoinHostPort(settings.ListenAddress, strconv.Itoa(settings.FluentForward.Port)),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if receivers[id], err = f.CreateLogs(ctx, receiver.Settings{
+			TelemetrySettings: p.componentSettings,
+			ID:                id,
+		}, cfg, fluentTagConsumer(p.logs, "com.splunk."+settings.FluentForward.Tag)); err != nil {
+			return nil, nil, err
+		}
+		p.logger.Info("Configured Fluent Forward receiver", zap.Int("port", settings.FluentForward.Port),
+			zap.String("tag", settings.FluentForward.Tag))
+	}
+
+	if settings.StatsD.Port != 0 {
+		// Timings, histograms and distributions are summaries of their percentiles over the interval.
+		var mappings []any
+		for _, statsdType := range []string{"timing", "histogram", "distribution"} {
+			mappings = append(mappings, map[string]any{
+				"statsd_type":   statsdType,
+				"observer_type": "summary",
+				"summary":       map[string]any{"percentiles": settings.StatsD.Percentiles},
+			})
+		}
+		f := statsdreceiver.NewFactory()
+		id := p.id(f.Type(), "")
+		cfg, err := receiverConfig(f, map[string]any{
+			"endpoint":                net.JoinHostPort(settings.ListenAddress, strconv.Itoa(settings.StatsD.Port)),
+			"aggregation_interval":    settings.StatsD.AggregationInterval,
+			"timer_histogram_mapping": mappings,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if receivers[id], err = f.CreateMetrics(ctx, receiver.Settings{
+			TelemetrySettings: p.componentSettings,
+			ID:                id,
+		}, cfg, p.metrics); err != nil {
+			return nil, nil, err
+		}
+		p.logger.Info("Configured StatsD receiver", zap.Int("port", settings.StatsD.Port),
+			zap.Duration("aggregation_interval", settings.StatsD.AggregationInterval))
+	}
+
+	if settings.GraphitePort != 0 {
+		f := carbonreceiver.NewFactory()
+		id := p.id(f.Type(), "")
+		cfg, err := receiverConfig(f, map[string]any{
+			"endpoint": net.JoinHostPort(settings.ListenAddress, strconv.Itoa(settings.GraphitePort)),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if receivers[id], err = f.CreateMetrics(ctx, receiver.Settings{
+			TelemetrySettings: p.componentSettings,
+			ID:                id,
+		}, cfg, p.metrics); err != nil {
+			return nil, nil, err
+		}
+		p.logger.Info("Configured Graphite receiver", zap.Int("port", settings.GraphitePort))
+	}
+	return receivers, auths, nil
+}
+
+// fluentTagConsumer passes the logs of the Fluent Forward receiver to next, with the tag of each record moved from
+// the fluent.tag attribute to attribute, so the tag is the source or the sourcetype of the events.
+func fluentTagConsumer(next consumer.Logs, attribute string) consumer.Logs {
+	c, _ := consumer.NewLogs(func(ctx context.Context, ld plog.Logs) error {
+		for _, rl := range ld.ResourceLogs().All() {
+			for _, sl := range rl.ScopeLogs().All() {
+				for _, lr := range sl.LogRecords().All() {
+					if tag, ok := lr.Attributes().Get(fluentTagAttribute); ok {
+						lr.Attributes().PutStr(attribute, tag.Str())
+						lr.Attributes().Remove(fluentTagAttribute)
+					}
+				}
+			}
+		}
+		return next.ConsumeLogs(ctx, ld)
+	}, consumer.WithCapabilities(consumer.Capabilities{MutatesData: true}))
+	return c
+}
+
+// receiverConfig returns the default configuration of the receivers of f, overridden by conf.
+func receiverConfig(f receiver.Factory, conf map[string]any) (component.Config, error) {
+	cfg := f.CreateDefaultConfig()
+	if err := confmap.NewFromStringMap(conf).Unmarshal(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// consumeLogs passes logs to the current exporters, which are not replaced until they consumed them.
+func (p *pipeline) consumeLogs(ctx context.Context, ld plog.Logs) error {
+	p.exportersMu.RLock()
+	defer p.exportersMu.RUnlock()
+	return p.exporters.logs.ConsumeLogs(ctx, ld)
+}
+
+// consumeMetrics passes metrics to the current exporters, which are not replaced until they consumed them.
+func (p *pipeline) consumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	p.exportersMu.RLock()
+	defer p.exportersMu.RUnlock()
+	return p.exporters.metrics.ConsumeMetrics(ctx, md)
+}
+
+// consumeTraces passes traces to the current exporters, which are not replaced until they consumed them.
+func (p *pipeline) consumeTraces(ctx context.Context, td ptrace.Traces) error {
+	p.exportersMu.RLock()
+	defer p.exportersMu.RUnlock()
+	return p.exporters.traces.ConsumeTraces(ctx, td)
+}
+
+// consumeTelemetry passes the self telemetry of the input to the current telemetry exporter.
+func (p *pipeline) consumeTelemetry(ctx context.Context, md pmetric.Metrics) error {
+	p.exportersMu.RLock()
+	defer p.exportersMu.RUnlock()
+	return p.exporters.telemetry.ConsumeMetrics(ctx, md)
+}
+
+// id returns the ID of a component of the pipeline, named after the input of the pipeline if any.
+func (p *pipeline) id(typ component.Type, name string) component.ID {
+	switch {
+	case p.input == "":
+	case name == "":
+		name = p.input
+	default:
+		name += "/" + p.input
+	}
+	return component.NewIDWithName(typ, componentName(name))
+}
+
+// componentName escapes the characters stanza names can contain but component names cannot, such as spaces. They
+// are percent-encoded, with the percent sign itself, so distinct stanza names keep distinct component names.
+func componentName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r != '%' && !unicode.In(r, unicode.Z, unicode.C, unicode.S) {
+			b.WriteRune(r)
+			continue
+		}
+		for _, c := range []byte(string(r)) {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// start starts the components of the pipeline, reporting their status to h. The extensions of the pipeline
+// are added to the extensions of h.
+func (p *pipeline) start(ctx context.Context, h *internal.TTYHost) (err error) {
+	if p.settings.HealthPort != 0 {
+		p.health = internal.NewHealthServer(p.settings.ListenAddress, p.settings.HealthPort, h, p.logger)
+		p.health.HandleLogLevel(p.logLevel)
+		if err = p.health.Start(); err != nil {
+			p.health = nil
+			return err
+		}
+		defer func() {
+			if err != nil {
+				_ = p.health.Shutdown(ctx)
+				p.health = nil
+			}
+		}()
+	}
+
+	for id, ext := range p.extensions {
+		h.Extensions[id] = ext
+	}
+	for id, ext := range p.extensions {
+		if err = startComponent(ctx, h, id, ext); err != nil {
+			return err
+		}
+	}
+
+	err = p.exporters.each(func(id component.ID, c component.Component) error {
+		return startComponent(ctx, h, id, c)
+	})
+	if err != nil {
+		return err
+	}
+	if p.capture != nil {
+		if err = startComponent(ctx, h, p.captureID, p.capture); err != nil {
+			return err
+		}
+	}
+	if err = p.startReceivers(ctx, h, p.receivers, p.auths); err != nil {
+		return err
+	}
+	if p.telemetry != nil {
+		if err = startComponent(ctx, h, p.telemetryID, p.telemetry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startReceivers starts receivers, and auths first, as the receivers of the pipeline.
+func (p *pipeline) startReceivers(ctx context.Context, h *internal.TTYHost, receivers map[component.ID]component.Component, auths map[component.ID]extension.Extension) error {
+	p.receivers, p.auths = receivers, auths
+	for id, auth := range auths {
+		h.Extensions[id] = auth
+		if err := startComponent(ctx, h, id, auth); err != nil {
+			return err
+		}
+	}
+	for id, r := range receivers {
+		if err := startComponent(ctx, h, id, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shutdown shuts the components of the pipeline down within the shutdown timeout, the receivers first so the
+// exporters drain what they accepted, and returns the errors of the components.
+func (p *pipeline) shutdown(ctx context.Context, h *internal.TTYHost) error {
+	ctx, cancel := context.WithTimeout(ctx, p.settings.ShutdownTimeout)
+	defer cancel()
+	before, statsErr := p.stats.Collect(ctx)
+
+	var errs []error
+	for id, r := range p.receivers {
+		errs = append(errs, shutdownComponent(ctx, h, id, r))
+	}
+	if p.capture != nil {
+		errs = append(errs, shutdownComponent(ctx, h, p.captureID, p.capture))
+	}
+	e := p.exporters
+	errs = append(errs,
+		shutdownComponent(ctx, h, e.logsID, e.logs),
+		shutdownComponent(ctx, h, e.tracesID, e.traces),
+		shutdownComponent(ctx, h, e.metricsID, e.metrics))
+	if statsErr == nil {
+		p.logDrain(ctx, before)
+	}
+
+	if p.telemetry != nil {
+		// Shut down after the other components, so the last export accounts for the data they flushed.
+		errs = append(errs,
+			shutdownComponent(ctx, h, p.telemetryID, p.telemetry),
+			shutdownComponent(ctx, h, e.telemetryID, e.telemetry))
+	}
+	for id, auth := range p.auths {
+		errs = append(errs, shutdownComponent(ctx, h, id, auth))
+	}
+	for id, ext := range p.extensions {
+		errs = append(errs, shutdownComponent(ctx, h, id, ext))
+	}
+	if p.meterProvider != nil {
+		errs = append(errs, p.meterProvider.Shutdown(ctx))
+	}
+	if p.health != nil {
+		errs = append(errs, p.health.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// abort shuts the pipeline down after it failed to start, and removes its components from h, so the pipeline
+// does not weigh on the health of the pipelines still running.
+func (p *pipeline) abort(ctx context.Context, h *internal.TTYHost) {
+	if err := p.shutdown(ctx, h); err != nil {
+		p.logger.Debug("Input did not shut down cleanly after failing to start", zap.Error(err))
+	}
+	ids := []component.ID{p.captureID, p.telemetryID}
+	_ = p.exporters.each(func(id component.ID, _ component.Component) error {
+		ids = append(ids, id)
+		return nil
+	})
+	for id := range p.receivers {
+		ids = append(ids, id)
+	}
+	for id := range p.auths {
+		ids = append(ids, id)
+	}
+	for id := range p.extensions {
+		ids = append(ids, id)
+	}
+	for _, id := range ids {
+		delete(h.Extensions, id)
+		h.RemoveComponentStatus(id)
+	}
+}
+
+// logDrain logs how many items the exporters flushed and dropped since before. When the shutdown timeout expired,
+// it also logs how many requests are left in their queues, and how many items of each signal the receivers
+// accepted but the exporters neither sent nor failed to send, which are discarded unless the queue is persistent.
+func (p *pipeline) logDrain(ctx context.Context, before internal.ExporterCounts) {
+	timedOut := ctx.Err() != nil
+	after, err := p.stats.Collect(context.WithoutCancel(ctx))
+	if err != nil {
+		return
+	}
+	drained := after.Sub(before)
+	fields := []zap.Field{zap.Int64("flushed", drained.Sent()), zap.Int64("dropped", drained.Failed())}
+	if !timedOut {
+		p.logger.Info("Drained the exporter queues", fields...)
+		return
+	}
+	msg, prefix := "Shutdown timed out, dropping the requests left in the exporter queues", "discarded_"
+	if p.settings.PersistentQueue {
+		msg, prefix = "Shutdown timed out, the requests left in the persistent queue are sent on the next start", "queued_"
+	}
+	p.logger.Warn(msg, append(fields,
+		zap.Int64("queued_requests", after.Queued),
+		zap.Int64(prefix+"log_records", after.Logs.Discarded()),
+		zap.Int64(prefix+"spans", after.Traces.Discarded()),
+		zap.Int64(prefix+"metric_points", after.Metrics.Discarded()),
+		zap.Duration("shutdown_timeout", p.settings.ShutdownTimeout))...)
+}
+
+// exporterConfig returns the configuration of the stdout exporter of a signal.
+func (p *pipeline) exporterConfig(settings internal.Settings, signal internal.SignalSettings) *stdoutexporter.Config {
+	cfg := stdoutexporter.NewFactory().CreateDefaultConfig().(*stdoutexporter.Config)
+	cfg.Config = eventConfig(settings, signal)
+	cfg.Stanza = settings.Name
+	if settings.PersistentQueue {
+		storageID := p.id(storageType, "")
+		cfg.QueueBatchConfig.Get().StorageID = &storageID
+	}
+	return cfg
+}
+
+// hecExporterConfig returns the configuration of the HEC exporter of a signal, authenticating with token.
+func (p *pipeline) hecExporterConfig(settings internal.Settings, signal internal.SignalSettings, token string) *hecexporter.Config {
+	cfg := hecexporter.NewFactory().CreateDefaultConfig().(*hecexporter.Config)
+	cfg.Config = eventConfig(settings, signal)
+	cfg.Endpoint = settings.HEC.Endpoint
+	cfg.Token = configopaque.String(token)
+	cfg.UseAck = settings.HEC.UseAck
+	cfg.TLS.CAFile = settings.HEC.CAFile
+	if settings.PersistentQueue {
+		storageID := p.id(storageType, "")
+		cfg.QueueBatchConfig.Get().StorageID = &storageID
+	}
+	return cfg
+}
+
+// eventConfig returns the defaults of the events of a signal and the indexes they may be routed to.
+func eventConfig(settings internal.Settings, signal internal.SignalSettings) splunkevent.Config {
+	return splunkevent.Config{
+		Index:                signal.Index,
+		SourceType:           signal.SourceType,
+		Source:               settings.Source,
+		Host:                 settings.Host,
+		AllowedIndexes:       settings.AllowedIndexes,
+		IndexViolationAction: settings.IndexViolationAction,
+	}
+}
+
+// createStorage returns the file storage extension backing the exporter queues with files in the queue
+// directory of the input.
+func (p *pipeline) createStorage(ctx context.Context) (extension.Extension, error) {
+	directory := p.settings.QueueDirectory()
+	f := filestorage.NewFactory()
+	cfg := f.CreateDefaultConfig().(*filestorage.Config)
+	cfg.Directory = directory
+	cfg.CreateDirectory = true
+	// Reclaim the space of the data delivered before the input was restarted.
+	cfg.Compaction.Directory = directory
+	cfg.Compaction.OnStart = true
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return f.Create(ctx, extension.Settings{
+		TelemetrySettings: p.componentSettings,
+		ID:                p.id(storageType, ""),
+	}, cfg)
+}
+
+// telemetryResource returns the resource of the self telemetry of the input stanza.
+func telemetryResource(stanza string) pcommon.Resource {
+	res := pcommon.NewResource()
+	res.Attributes().PutStr("service.name", "splunk-connect-for-otlp")
+	res.Attributes().PutStr("com.splunk.source", stanza)
+	return res
+}
+
+// serverConfig returns the configuration of an HTTP or gRPC server of a receiver listening on port.
+func (p *pipeline) serverConfig(settings internal.Settings, port int) map[string]any {
+	cfg := map[string]any{"endpoint": net.JoinHostPort(settings.ListenAddress, strconv.Itoa(port))}
+	if settings.Capture.Enabled {
+		// Pass the headers of the requests to the capture.
+		cfg["include_metadata"] = true
+	}
+	if len(settings.AuthTokens) > 0 {
+		cfg["auth"] = map[string]any{"authenticator": p.authID.String()}
+	}
+	if settings.TLS.Enabled() {
+		cfg["tls"] = tlsConfig(settings)
+	}
+	return cfg
+}
+
+// tlsConfig returns the configuration of the TLS server of the receivers.
+func tlsConfig(settings internal.Settings) map[string]any {
+	return map[string]any{
+		"cert_file":      settings.TLS.CertFile,
+		"key_file":       settings.TLS.KeyFile,
+		"client_ca_file": settings.TLS.ClientCAFile,
+		"min_version":    settings.TLS.MinVersion,
+	}
+}
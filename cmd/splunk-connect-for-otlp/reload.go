@@ -0,0 +1,249 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/splunk/otlp2splunk/internal"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.uber.org/zap"
+)
+
+// loadFunc returns the current configuration of the inputs, to reload their pipelines.
+type loadFunc func(ctx context.Context) ([]internal.XMLInput, error)
+
+// reloadPipelines applies the current configuration of the inputs to their pipelines. Inputs added to or removed
+// from the configuration are only served or stopped when the process restarts. Nothing is reloaded when several
+// inputs would listen on the same port, as their receivers could not all start.
+func reloadPipelines(ctx context.Context, h *internal.TTYHost, logger *zap.Logger, pipelines []*pipeline, load loadFunc) {
+	inputs, err := load(ctx)
+	if err != nil {
+		logger.Error("Cannot read the input configuration to reload", zap.Error(err))
+		return
+	}
+	configs := make(map[string]internal.XMLInput, len(inputs))
+	for _, input := range inputs {
+		configs[input.Configuration.Stanza.Name] = input
+	}
+	// Inputs which were removed or whose configuration is invalid keep their current settings.
+	type stanzaReload struct {
+		p      *pipeline
+		config internal.XMLInput
+		next   internal.Settings
+	}
+	reloads := make([]stanzaReload, 0, len(pipelines))
+	merged := make([]internal.Settings, 0, len(pipelines))
+	for _, p := range pipelines {
+		config, ok := configs[p.settings.Name]
+		if !ok {
+			p.logger.Warn("The input was removed from the configuration, it stops when the process restarts")
+			merged = append(merged, p.settings)
+			continue
+		}
+		delete(configs, p.settings.Name)
+		next, extractErr := config.Extract()
+		if extractErr != nil {
+			p.logger.Error("Cannot reload the input configuration", zap.Error(fmt.Errorf("the input configuration is invalid: %w", extractErr)))
+			merged = append(merged, p.settings)
+			continue
+		}
+		applied, _ := p.settings.Changes(next)
+		reloads = append(reloads, stanzaReload{p: p, config: config, next: next})
+		merged = append(merged, applied)
+	}
+	for name := range configs {
+		logger.Warn("The input was added to the configuration, it starts when the process restarts", zap.String("stanza", name))
+	}
+	if err = internal.CheckListeners(merged); err != nil {
+		logger.Error("Refusing to reload the input configuration, several inputs would listen on the same port", zap.Error(err))
+		return
+	}
+	for _, r := range reloads {
+		if err = r.p.reload(ctx, h, r.config, r.next); err != nil {
+			r.p.logger.Error("Cannot reload the input configuration", zap.Error(err))
+		}
+	}
+}
+
+// reload applies next, the settings extracted from config, the current configuration of the input, rebuilding only
+// the components whose settings changed: the receivers when the listeners or the values of their tokens changed,
+// the exporters when the defaults or the destination of the events changed, including the value of the HEC token.
+// Parameters that only apply when the input restarts keep their current value.
+func (p *pipeline) reload(ctx context.Context, h *internal.TTYHost, config internal.XMLInput, next internal.Settings) error {
+	settings, changes := p.settings.Changes(next)
+	// A secret can change in Splunk while its name stays the same in the stanza.
+	secrets, err := fetchSecrets(ctx, config, settings)
+	if err != nil {
+		return err
+	}
+	changes.Receiver = changes.Receiver || !slices.Equal(secrets.authTokens, p.secrets.authTokens)
+	changes.Exporters = changes.Exporters || secrets.hecToken != p.secrets.hecToken
+	if len(changes.Restart) > 0 {
+		p.logger.Warn("Changed parameters apply when the input restarts", zap.Strings("params", changes.Restart))
+	}
+	if settings.Log.Level != p.settings.Log.Level {
+		p.logLevel.SetLevel(settings.Log.Level)
+		p.logger.Info("Changed log level", zap.Stringer("level", settings.Log.Level))
+	}
+	p.settings.Log.Level = settings.Log.Level
+	p.settings.ShutdownTimeout = settings.ShutdownTimeout
+	if !changes.Exporters && !changes.Receiver {
+		p.logger.Debug("Input configuration unchanged")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, settings.ShutdownTimeout)
+	defer cancel()
+	// The settings are only updated once every component was rebuilt, so the next reload retries what failed.
+	if changes.Exporters {
+		if err = p.replaceExporters(ctx, h, settings, secrets.hecToken); err != nil {
+			return fmt.Errorf("cannot replace the exporters: %w", err)
+		}
+	}
+	if changes.Receiver {
+		if err = p.replaceReceivers(ctx, h, settings, secrets.authTokens); err != nil {
+			return fmt.Errorf("cannot replace the receivers: %w", err)
+		}
+	}
+	p.settings = settings
+	p.secrets = secrets
+	p.logger.Info("Reloaded input configuration", zap.Bool("receiver", changes.Receiver), zap.Bool("exporters", changes.Exporters))
+	return nil
+}
+
+// replaceExporters replaces the exporters with exporters configured with settings and hecToken. The current
+// exporters drain their queue while the new exporters receive the data. Persistent queues are handed over instead:
+// the current exporters stop before the new exporters open the same queue, and data received meanwhile waits for
+// them.
+func (p *pipeline) replaceExporters(ctx context.Context, h *internal.TTYHost, settings internal.Settings, hecToken string) error {
+	next, err := p.createExporters(ctx, settings, hecToken)
+	if err != nil {
+		return err
+	}
+	start := func(id component.ID, c component.Component) error {
+		return startComponent(ctx, h, id, c)
+	}
+
+	if !settings.PersistentQueue {
+		if err = next.each(start); err != nil {
+			p.logError(p.stopExporters(ctx, h, next, p.exporters))
+			p.reportRunning(h, p.exporters)
+			return err
+		}
+		p.exportersMu.Lock()
+		current := p.exporters
+		p.exporters = next
+		p.exportersMu.Unlock()
+		p.logError(p.stopExporters(ctx, h, current, next))
+		return nil
+	}
+
+	p.exportersMu.Lock()
+	defer p.exportersMu.Unlock()
+	current := p.exporters
+	p.logError(p.stopExporters(ctx, h, current, next))
+	if err = next.each(start); err == nil {
+		p.exporters = next
+		return nil
+	}
+	p.logError(p.stopExporters(ctx, h, next, current))
+	// The current exporters are stopped: export with the current settings again, so the input keeps running.
+	previous, prevErr := p.createExporters(ctx, p.settings, p.secrets.hecToken)
+	if prevErr == nil {
+		prevErr = previous.each(start)
+	}
+	if prevErr != nil {
+		p.fail(h, current.logsID, prevErr)
+		return errors.Join(err, prevErr)
+	}
+	p.exporters = previous
+	return err
+}
+
+// stopExporters shuts the exporters of old down, once replaced by the exporters of current. The exporters of
+// old that current does not have a replacement for are removed from the statuses of h.
+func (p *pipeline) stopExporters(ctx context.Context, h *internal.TTYHost, old, current *exporterSet) error {
+	replaced := map[component.ID]bool{}
+	_ = current.each(func(id component.ID, _ component.Component) error {
+		replaced[id] = true
+		return nil
+	})
+	var errs []error
+	_ = old.each(func(id component.ID, c component.Component) error {
+		if replaced[id] {
+			errs = append(errs, shutdownWithin(ctx, id, c))
+			return nil
+		}
+		errs = append(errs, shutdownComponent(ctx, h, id, c))
+		h.RemoveComponentStatus(id)
+		return nil
+	})
+	return errors.Join(errs...)
+}
+
+// reportRunning reports the exporters of e run, after exporters sharing their IDs failed to replace them.
+func (p *pipeline) reportRunning(h *internal.TTYHost, e *exporterSet) {
+	_ = e.each(func(id component.ID, _ component.Component) error {
+		h.ReportComponentStatus(id, componentstatus.NewEvent(componentstatus.StatusOK))
+		return nil
+	})
+}
+
+// replaceReceivers replaces the receivers with receivers configured with settings. The current receivers stop
+// first, to release their ports. If the new receivers cannot start, for example because a port is in use, the
+// receivers listen with the current settings again.
+func (p *pipeline) replaceReceivers(ctx context.Context, h *internal.TTYHost, settings internal.Settings, tokens []string) error {
+	receivers, auths, err := p.createReceivers(ctx, settings, tokens)
+	if err != nil {
+		return err
+	}
+	p.logError(p.stopReceivers(ctx, h))
+	if err = p.startReceivers(ctx, h, receivers, auths); err == nil {
+		return nil
+	}
+	p.logError(p.stopReceivers(ctx, h))
+	previous, prevAuths, prevErr := p.createReceivers(ctx, p.settings, p.secrets.authTokens)
+	if prevErr == nil {
+		prevErr = p.startReceivers(ctx, h, previous, prevAuths)
+	}
+	if prevErr != nil {
+		p.fail(h, p.receiverID, prevErr)
+		return errors.Join(err, prevErr)
+	}
+	return err
+}
+
+// stopReceivers shuts the receivers down, and the extensions checking their tokens. They are removed from the
+// statuses of h, as the receivers replacing them may not include every receiver.
+func (p *pipeline) stopReceivers(ctx context.Context, h *internal.TTYHost) error {
+	var errs []error
+	for id, r := range p.receivers {
+		errs = append(errs, shutdownComponent(ctx, h, id, r))
+		h.RemoveComponentStatus(id)
+	}
+	for id, auth := range p.auths {
+		errs = append(errs, shutdownComponent(ctx, h, id, auth))
+		delete(h.Extensions, id)
+		h.RemoveComponentStatus(id)
+	}
+	return errors.Join(errs...)
+}
+
+// fail reports the component identified by id failed fatally, which stops the input.
+func (p *pipeline) fail(h *internal.TTYHost, id component.ID, err error) {
+	p.logger.Error("Cannot restore the input after a failed reload", zap.Error(err))
+	componentstatus.ReportStatus(h.ForComponent(id), componentstatus.NewFatalErrorEvent(err))
+}
+
+// logError logs err, returned by the shutdown of a replaced component, if not nil.
+func (p *pipeline) logError(err error) {
+	if err != nil {
+		p.logger.Warn("Replaced component did not shut down cleanly", zap.Error(err))
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/splunk/otlp2splunk/internal"
+)
+
+// transportHeaders lists the headers describing how the original request was transported, which do not apply
+// to the replayed protobuf payload.
+var transportHeaders = map[string]bool{
+	"connection":        true,
+	"content-encoding":  true,
+	"content-length":    true,
+	"content-type":      true,
+	"host":              true,
+	"te":                true,
+	"transfer-encoding": true,
+}
+
+// replay posts the requests of capture files to the OTLP/HTTP endpoint of an input, so the events a sender
+// produced can be reproduced. Requests are paced like they were received, sped up by the speed factor, or
+// sent as fast as possible when speed is 0.
+func replay(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	endpoint := flags.String("endpoint", fmt.Sprintf("http://localhost:%d", internal.DefaultHTTPPort), "URL of the OTLP/HTTP endpoint requests are posted to")
+	speed := flags.Float64("speed", 1, "factor the requests are sped up by: 1 replays them at their original pace, 0 as fast as possible")
+	token := flags.String("token", "", "bearer token authenticating the requests")
+	caFile := flags.String("ca-file", "", "CA certificate verifying the endpoint. The system roots are used when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *speed < 0 {
+		return fmt.Errorf("speed %v must not be negative", *speed)
+	}
+	u, err := url.Parse(*endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("endpoint %q must be an http or https URL", *endpoint)
+	}
+	paths, err := expandPaths(flags.Args())
+	if err != nil {
+		return err
+	}
+	client, err := replayClient(*caFile)
+	if err != nil {
+		return err
+	}
+
+	r := &replayer{
+		client:   client,
+		endpoint: strings.TrimSuffix(u.String(), "/"),
+		speed:    *speed,
+		token:    *token,
+	}
+	for _, path := range paths {
+		if err = r.replayPath(path, stdin); err != nil {
+			return fmt.Errorf("cannot replay %s: %w", path, err)
+		}
+	}
+	_, err = fmt.Fprintf(stdout, "Replayed %d requests\n", r.count)
+	return err
+}
+
+func replayClient(caFile string) (*http.Client, error) {
+	if caFile == "" {
+		return http.DefaultClient, nil
+	}
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("ca-file %s does not contain any PEM certificate", caFile)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	return &http.Client{Transport: transport}, nil
+}
+
+// replayer posts capture records to an OTLP/HTTP endpoint.
+type replayer struct {
+	client   *http.Client
+	endpoint string
+	speed    float64
+	token    string
+
+	// last is the time the previous record was received at.
+	last  time.Time
+	count int
+}
+
+func (r *replayer) replayPath(path string, stdin io.Reader) error {
+	if path == stdinPath {
+		return internal.ReadCapture(stdin, r.replay)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return internal.ReadCapture(f, r.replay)
+}
+
+// replay waits for the time elapsed between the previous record and record, then posts record.
+func (r *replayer) replay(record internal.CaptureRecord) error {
+	switch record.Signal {
+	case "logs", "metrics", "traces":
+	default:
+		return fmt.Errorf("signal %q is not supported, it must be one of logs, metrics or traces", record.Signal)
+	}
+	if r.speed > 0 && !r.last.IsZero() && record.Time.After(r.last) {
+		time.Sleep(time.Duration(float64(record.Time.Sub(r.last)) / r.speed))
+	}
+	r.last = record.Time
+
+	req, err := http.NewRequest(http.MethodPost, r.endpoint+"/v1/"+record.Signal, bytes.NewReader(record.Payload))
+	if err != nil {
+		return err
+	}
+	for key, values := range record.Headers {
+		// gRPC pseudo-headers start with a colon.
+		if transportHeaders[strings.ToLower(key)] || strings.HasPrefix(key, ":") {
+			continue
+		}
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("request %d was refused: %s %s", r.count+1, resp.Status, strings.TrimSpace(string(body)))
+	}
+	r.count++
+	return nil
+}
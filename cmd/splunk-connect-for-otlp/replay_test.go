@@ -0,0 +1,76 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/splunk/otlp2splunk/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+// runInput runs the input configured by config until fn returns, and returns the stdout lines fn waited for.
+func runInput(t *testing.T, config string, fn func(lines <-chan string) []string) []string {
+	restoreStdin := testutils.WriteToStdin(t, config)
+	defer restoreStdin()
+	stdoutLines, restoreStdout := testutils.CaptureStdoutLines(t)
+	defer restoreStdout()
+
+	stop := startInput(t, run)
+	lines := fn(stdoutLines)
+
+	require.NoError(t, stop())
+	return lines
+}
+
+func TestReplayCapturedRequests(t *testing.T) {
+	checkpointDir := t.TempDir()
+	stanza := `<stanza name="splunk-connect-for-otlp://test" app="search"><param name="grpc_port">%d</param><param name="http_port">%d</param><param name="listen_address">127.0.0.1</param>%s</stanza>`
+
+	var captured []string
+	for _, signal := range []string{"logs", "metrics", "traces"} {
+		payload, err := os.ReadFile(filepath.Join("testdata", "otlp_"+signal+".json"))
+		require.NoError(t, err)
+		expected := testutils.LoadExpectedHecData(t, filepath.Join("testdata", "expected_hec_"+signal+".json"))
+		events := len(strings.Split(strings.TrimSpace(string(expected)), "\n"))
+		httpPort := testutils.GetFreePort(t)
+		config := fmt.Sprintf(`<input><checkpoint_dir>%s</checkpoint_dir><configuration>`+stanza+`</configuration></input>`,
+			checkpointDir, testutils.GetFreePort(t), httpPort, `<param name="capture">true</param>`)
+		captured = append(captured, runInput(t, config, func(lines <-chan string) []string {
+			testutils.PostOTLP(t, httpPort, "/v1/"+signal, payload)
+			return testutils.CollectEvents(t, lines, events)
+		})...)
+	}
+
+	httpPort := testutils.GetFreePort(t)
+	config := fmt.Sprintf(`<input><configuration>`+stanza+`</configuration></input>`,
+		testutils.GetFreePort(t), httpPort, "")
+	var out bytes.Buffer
+	replayed := runInput(t, config, func(lines <-chan string) []string {
+		// An empty request waits for the input to listen, without writing any event.
+		testutils.PostOTLP(t, httpPort, "/v1/logs", []byte(`{}`))
+		require.NoError(t, replay([]string{"--endpoint", fmt.Sprintf("http://127.0.0.1:%d", httpPort), "--speed", "0",
+			filepath.Join(checkpointDir, "capture", "test")}, nil, &out))
+		return testutils.CollectEvents(t, lines, len(captured))
+	})
+	require.Equal(t, "Replayed 3 requests\n", out.String())
+	require.Equal(t, captured, replayed)
+}
+
+func TestReplayErrors(t *testing.T) {
+	capture := filepath.Join(t.TempDir(), "capture.jsonl")
+	require.NoError(t, os.WriteFile(capture, []byte(`{"signal":"profiles","payload":""}`+"\n"), 0o600))
+
+	require.EqualError(t, replay([]string{"--endpoint", "localhost:4318", capture}, nil, &bytes.Buffer{}),
+		`endpoint "localhost:4318" must be an http or https URL`)
+	require.EqualError(t, replay([]string{"--speed", "-1", capture}, nil, &bytes.Buffer{}),
+		"speed -1 must not be negative")
+	require.EqualError(t, replay([]string{capture}, nil, &bytes.Buffer{}),
+		fmt.Sprintf(`cannot replay %s: signal "profiles" is not supported, it must be one of logs, metrics or traces`, capture))
+}
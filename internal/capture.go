@@ -0,0 +1,248 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+var _ component.Component = &Capture{}
+
+const (
+	// captureFileName is the name of the capture file requests are written to. Rotated files are named after
+	// the time they were rotated at, so they sort in the order they were written.
+	captureFileName   = "capture.jsonl"
+	captureFilePrefix = "capture-"
+	captureTimeLayout = "20060102T150405.000000000"
+)
+
+// redactedHeaders lists the headers holding credentials, which are not captured.
+var redactedHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+}
+
+// CaptureRecord is a request received by the input, as written to the capture file.
+type CaptureRecord struct {
+	Time time.Time `json:"time"`
+	// Signal is logs, metrics or traces.
+	Signal string `json:"signal"`
+	// Headers holds the HTTP headers or gRPC metadata of the request, without credentials.
+	Headers map[string][]string `json:"headers,omitempty"`
+	// Payload is the request encoded as OTLP protobuf.
+	Payload []byte `json:"payload"`
+}
+
+// Capture writes every request received by the input to a capture file in a directory, so it can be replayed.
+// The file is rotated once it reaches a maximum size, and only the most recent rotated files are kept.
+type Capture struct {
+	logger   *zap.Logger
+	dir      string
+	maxSize  int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewCapture returns a capture writing to dir, rotating the capture file after maxSize bytes and keeping
+// maxFiles rotated files.
+func NewCapture(logger *zap.Logger, dir string, maxSize int64, maxFiles int) *Capture {
+	return &Capture{logger: logger, dir: dir, maxSize: maxSize, maxFiles: maxFiles}
+}
+
+// Start opens the capture file, appending to the file of the previous run if any.
+func (c *Capture) Start(_ context.Context, _ component.Host) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	return c.open()
+}
+
+// Shutdown closes the capture file.
+func (c *Capture) Shutdown(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
+
+// Logs returns a consumer capturing the logs it receives before passing them to next.
+func (c *Capture) Logs(next consumer.Logs) consumer.Logs {
+	marshaler := &plog.ProtoMarshaler{}
+	logs, _ := consumer.NewLogs(func(ctx context.Context, ld plog.Logs) error {
+		payload, err := marshaler.MarshalLogs(ld)
+		c.record(ctx, "logs", payload, err)
+		return next.ConsumeLogs(ctx, ld)
+	}, consumer.WithCapabilities(next.Capabilities()))
+	return logs
+}
+
+// Metrics returns a consumer capturing the metrics it receives before passing them to next.
+func (c *Capture) Metrics(next consumer.Metrics) consumer.Metrics {
+	marshaler := &pmetric.ProtoMarshaler{}
+	metrics, _ := consumer.NewMetrics(func(ctx context.Context, md pmetric.Metrics) error {
+		payload, err := marshaler.MarshalMetrics(md)
+		c.record(ctx, "metrics", payload, err)
+		return next.ConsumeMetrics(ctx, md)
+	}, consumer.WithCapabilities(next.Capabilities()))
+	return metrics
+}
+
+// Traces returns a consumer capturing the traces it receives before passing them to next.
+func (c *Capture) Traces(next consumer.Traces) consumer.Traces {
+	marshaler := &ptrace.ProtoMarshaler{}
+	traces, _ := consumer.NewTraces(func(ctx context.Context, td ptrace.Traces) error {
+		payload, err := marshaler.MarshalTraces(td)
+		c.record(ctx, "traces", payload, err)
+		return next.ConsumeTraces(ctx, td)
+	}, consumer.WithCapabilities(next.Capabilities()))
+	return traces
+}
+
+// record writes a request to the capture file. Capture failures are logged, they never fail the request.
+func (c *Capture) record(ctx context.Context, signal string, payload []byte, err error) {
+	if err == nil {
+		err = c.write(CaptureRecord{
+			Time:    time.Now().UTC(),
+			Signal:  signal,
+			Headers: capturedHeaders(client.FromContext(ctx).Metadata),
+			Payload: payload,
+		})
+	}
+	if err != nil {
+		c.logger.Warn("Cannot capture request", zap.String("signal", signal), zap.Error(err))
+	}
+}
+
+func capturedHeaders(md client.Metadata) map[string][]string {
+	headers := map[string][]string{}
+	for key := range md.Keys() {
+		if !redactedHeaders[strings.ToLower(key)] {
+			headers[key] = md.Get(key)
+		}
+	}
+	return headers
+}
+
+func (c *Capture) write(record CaptureRecord) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return fmt.Errorf("capture %s is closed", c.dir)
+	}
+	if c.size > 0 && c.size+int64(len(b)) > c.maxSize {
+		if err = c.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := c.file.Write(b)
+	c.size += int64(n)
+	return err
+}
+
+func (c *Capture) open() error {
+	f, err := os.OpenFile(filepath.Join(c.dir, captureFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	c.file = f
+	c.size = info.Size()
+	return nil
+}
+
+// rotate renames the capture file after the current time, removes the oldest rotated files and opens a new file.
+func (c *Capture) rotate() error {
+	if err := c.file.Close(); err != nil {
+		return err
+	}
+	c.file = nil
+	rotated := filepath.Join(c.dir, captureFilePrefix+time.Now().UTC().Format(captureTimeLayout)+".jsonl")
+	if err := os.Rename(filepath.Join(c.dir, captureFileName), rotated); err != nil {
+		return err
+	}
+	files, err := CaptureFiles(c.dir)
+	if err != nil {
+		return err
+	}
+	// The capture file was renamed, so files only lists rotated files.
+	for len(files) > c.maxFiles {
+		if err = os.Remove(files[0]); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return c.open()
+}
+
+// CaptureFiles returns the capture files of dir in the order they were written: the rotated files, oldest
+// first, then the current capture file.
+func CaptureFiles(dir string) ([]string, error) {
+	rotated, err := filepath.Glob(filepath.Join(dir, captureFilePrefix+"*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(rotated)
+	current := filepath.Join(dir, captureFileName)
+	if _, err = os.Stat(current); err == nil {
+		rotated = append(rotated, current)
+	}
+	return rotated, nil
+}
+
+// ReadCapture calls fn with every record of a capture file, in the order they were written.
+func ReadCapture(r io.Reader, fn func(CaptureRecord) error) error {
+	scanner := bufio.NewScanner(r)
+	// Requests are limited by the receivers, not by the default token size of the scanner.
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var record CaptureRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("line %d is not a capture record: %w", line, err)
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
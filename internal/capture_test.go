@@ -0,0 +1,97 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+)
+
+func TestCaptureRecordsRequests(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "capture", "team_a")
+	capture := NewCapture(zap.NewNop(), dir, 1<<20, 2)
+	ctx := context.Background()
+	require.NoError(t, capture.Start(ctx, nil))
+
+	sink := &consumertest.LogsSink{}
+	logs := capture.Logs(sink)
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("hello")
+	reqCtx := client.NewContext(ctx, client.Info{Metadata: client.NewMetadata(map[string][]string{
+		"Content-Type":  {"application/x-protobuf"},
+		"Authorization": {"Bearer secret"},
+		"Cookie":        {"session=secret"},
+	})})
+	require.NoError(t, logs.ConsumeLogs(reqCtx, ld))
+	require.NoError(t, capture.Shutdown(ctx))
+	require.Equal(t, 1, sink.LogRecordCount(), "captured requests must be passed on")
+
+	files, err := CaptureFiles(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "capture.jsonl")}, files)
+
+	f, err := os.Open(files[0])
+	require.NoError(t, err)
+	defer f.Close()
+	var records []CaptureRecord
+	require.NoError(t, ReadCapture(f, func(record CaptureRecord) error {
+		records = append(records, record)
+		return nil
+	}))
+	require.Len(t, records, 1)
+	require.Equal(t, "logs", records[0].Signal)
+	require.Equal(t, map[string][]string{"content-type": {"application/x-protobuf"}}, records[0].Headers,
+		"credentials must not be captured")
+	replayed, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(records[0].Payload)
+	require.NoError(t, err)
+	require.Equal(t, "hello", replayed.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+}
+
+func TestCaptureRotation(t *testing.T) {
+	dir := t.TempDir()
+	// Each record is larger than the maximum size, so every record but the first rotates the file.
+	capture := NewCapture(zap.NewNop(), dir, 10, 2)
+	ctx := context.Background()
+	require.NoError(t, capture.Start(ctx, nil))
+	logs := capture.Logs(&consumertest.LogsSink{})
+	for range 5 {
+		ld := plog.NewLogs()
+		ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		require.NoError(t, logs.ConsumeLogs(ctx, ld))
+	}
+	require.NoError(t, capture.Shutdown(ctx))
+
+	files, err := CaptureFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 3, "the capture file and the 2 most recent rotated files must be kept")
+	require.Equal(t, filepath.Join(dir, "capture.jsonl"), files[2])
+
+	// Restarting appends to the capture file of the previous run.
+	require.NoError(t, capture.Start(ctx, nil))
+	require.NoError(t, capture.Shutdown(ctx))
+	after, err := CaptureFiles(dir)
+	require.NoError(t, err)
+	require.Equal(t, files, after)
+}
+
+func TestReadCaptureInvalidRecord(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "capture")
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"signal":"logs","payload":""}` + "\nnot json\n")
+	require.NoError(t, err)
+	_, err = f.Seek(0, 0)
+	require.NoError(t, err)
+	defer f.Close()
+
+	err = ReadCapture(f, func(CaptureRecord) error { return nil })
+	require.ErrorContains(t, err, "line 2 is not a capture record")
+}
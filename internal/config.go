@@ -6,22 +6,91 @@ package internal
 import (
 	"bufio"
 	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
 )
 
 const (
 	DefaultGrpcPort      = 4317
 	DefaultHTTPPort      = 4318
 	DefaultListenAddress = "0.0.0.0"
+
+	DefaultIndexViolationAction = "drop"
+	DefaultOutputMode           = "stdout"
+	DefaultLogFormat            = "json"
+	DefaultSyslogProtocol       = "rfc5424"
+	DefaultFluentForwardTag     = "source"
+
+	DefaultTelemetryIndex    = "_metrics"
+	DefaultTelemetryInterval = time.Minute
+
+	DefaultShutdownTimeout = 10 * time.Second
+
+	DefaultStatsDAggregationInterval = time.Minute
+
+	// DefaultCaptureMaxSize is the size, in megabytes, the capture file is rotated at.
+	DefaultCaptureMaxSize  = 100
+	DefaultCaptureMaxFiles = 10
 )
 
 type XMLInput struct {
+	ServerHost    string    `xml:"server_host"`
+	ServerURI     string    `xml:"server_uri"`
+	SessionKey    string    `xml:"session_key"`
+	CheckpointDir string    `xml:"checkpoint_dir"`
 	Configuration XMLConfig `xml:"configuration"`
+	// Item holds the stanza sent by Splunk when it asks the input to validate its arguments.
+	Item XMLStanza `xml:"item"`
+	// Secrets holds the secrets of inputs defined in a configuration file. Secrets are read from Splunk when nil.
+	Secrets map[string]string `xml:"-"`
+	// SharedProcess is true when the stanza is served by a process serving other stanzas too.
+	SharedProcess bool `xml:"-"`
 }
 
+// XMLConfig holds the stanzas splunkd sends to the input. The input runs in single instance mode, so splunkd
+// sends every enabled stanza at once.
 type XMLConfig struct {
-	Stanza XMLStanza `xml:"stanza"`
+	// Stanza is the first stanza, the stanza of the input once Inputs split the configuration.
+	Stanza  XMLStanza
+	Stanzas []XMLStanza
+}
+
+// UnmarshalXML reads the stanza elements of the configuration.
+func (c *XMLConfig) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var config struct {
+		Stanzas []XMLStanza `xml:"stanza"`
+	}
+	if err := d.DecodeElement(&config, &start); err != nil {
+		return err
+	}
+	c.Stanzas = config.Stanzas
+	if len(c.Stanzas) > 0 {
+		c.Stanza = c.Stanzas[0]
+	}
+	return nil
+}
+
+// Inputs returns an input per stanza of the configuration, sharing the server, the session key and the
+// checkpoint directory of x.
+func (x XMLInput) Inputs() []XMLInput {
+	inputs := make([]XMLInput, 0, len(x.Configuration.Stanzas))
+	for _, stanza := range x.Configuration.Stanzas {
+		input := x
+		input.Configuration = XMLConfig{Stanza: stanza, Stanzas: []XMLStanza{stanza}}
+		input.SharedProcess = len(x.Configuration.Stanzas) > 1
+		inputs = append(inputs, input)
+	}
+	return inputs
 }
 
 type XMLStanza struct {
@@ -35,23 +104,727 @@ type XMLParam struct {
 	Value string `xml:",innerxml"`
 }
 
-func (x XMLInput) Extract() (int, int, string) {
-	grpcPort := DefaultGrpcPort
-	httpPort := DefaultHTTPPort
-	listeningAddress := DefaultListenAddress
+// Settings holds the validated configuration of an input stanza.
+type Settings struct {
+	// Name is the name of the stanza.
+	Name          string
+	ListenAddress string
+	TLS           TLSSettings
+	// Index, Source and Host are the defaults of events lacking the corresponding resource attribute.
+	Index  string
+	Source string
+	Host   string
+	// Logs, Traces and Metrics hold the defaults specific to each signal. Their index falls back to Index.
+	// The sourcetype of the stanza is not used: splunkd parses the output of the input with it, so it is not
+	// the sourcetype of the events.
+	Logs    SignalSettings
+	Traces  SignalSettings
+	Metrics SignalSettings
+	// AllowedIndexes restricts the indexes events may be routed to with the com.splunk.index attribute.
+	// Each entry is an index name or a regular expression matching whole index names.
+	AllowedIndexes []string
+	// IndexViolationAction applies to events routed to an index that is not allowed: drop, default or reject.
+	IndexViolationAction string
+	// AuthTokens names the secrets, stored in Splunk under PasswordRealm, holding the bearer tokens
+	// accepted by the OTLP listeners.
+	AuthTokens []string
+	GRPCPort   int
+	HTTPPort   int
+	// AllowUnauthenticated accepts, with AuthTokens, the receivers which cannot check the tokens. They are refused
+	// otherwise, so setting AuthTokens does not leave a port open to any client by mistake.
+	AllowUnauthenticated bool
+	// HealthPort is the port of the health endpoint. The endpoint is disabled when 0.
+	HealthPort int
+	// PrometheusRemoteWritePort is the port of the Prometheus remote write receiver, passing the samples it
+	// receives to the metrics exporter. The receiver is disabled when 0.
+	PrometheusRemoteWritePort int
+	// ZipkinPort is the port of the Zipkin receiver, accepting Zipkin v1 and v2 spans in JSON or protobuf.
+	// The receiver is disabled when 0.
+	ZipkinPort int
+	// JaegerGRPCPort and JaegerHTTPPort are the ports of the Jaeger receiver, accepting spans over gRPC and
+	// Thrift over HTTP. Each protocol is disabled when its port is 0.
+	JaegerGRPCPort int
+	JaegerHTTPPort int
+	// HECPort is the port of the HEC receiver, accepting the events and metrics of HTTP Event Collector clients.
+	// The receiver is disabled when 0.
+	HECPort int
+	// Syslog configures the syslog receivers.
+	Syslog SyslogSettings
+	// FluentForward configures the receiver of the Fluent Forward protocol.
+	FluentForward FluentForwardSettings
+	// StatsD configures the StatsD receiver.
+	StatsD StatsDSettings
+	// GraphitePort is the port of the Graphite receiver, accepting metrics in the Carbon plaintext protocol over TCP.
+	// The receiver is disabled when 0.
+	GraphitePort int
+	// Telemetry configures the metrics the input exports about itself.
+	Telemetry TelemetrySettings
+	// PersistentQueue backs the exporter queues with files under CheckpointDir, so buffered data survives restarts.
+	PersistentQueue bool
+	// CheckpointDir is the directory Splunk provides to the input to persist its state.
+	CheckpointDir string
+	// OutputMode is where events are sent: stdout, to be read by splunkd, or hec.
+	OutputMode string
+	// HEC configures the HTTP Event Collector events are sent to when OutputMode is hec.
+	HEC HECSettings
+	// Capture configures the recording of the requests received by the input.
+	Capture CaptureSettings
+	// Log configures the diagnostic logs the input writes to stderr.
+	Log LogSettings
+	// ShutdownTimeout bounds the time the input waits for the exporter queues to drain when it stops.
+	ShutdownTimeout time.Duration
+	// ReloadInterval is the period between two reads of the stanza from Splunk, to apply its changes.
+	// Polling is disabled when 0.
+	ReloadInterval time.Duration
+}
+
+// LogSettings configures the diagnostic logs of the input.
+type LogSettings struct {
+	// Level is the minimum level of the logs written. It can be changed while the input runs.
+	Level zapcore.Level
+	// Format is json, or splunkd for key=value lines splunkd parses the level of.
+	Format string
+}
+
+// CaptureSettings configures the recording of received requests to capture files under CheckpointDir,
+// so they can be replayed.
+type CaptureSettings struct {
+	Enabled bool
+	// MaxSize is the size, in megabytes, the capture file is rotated at.
+	MaxSize int
+	// MaxFiles is the number of rotated capture files kept.
+	MaxFiles int
+}
+
+// SyslogSettings configures the receivers of syslog messages, passing them to the logs exporter.
+type SyslogSettings struct {
+	// TCPPort and UDPPort are the ports of the receivers over TCP and UDP. Each receiver is disabled when its
+	// port is 0.
+	TCPPort int
+	UDPPort int
+	// Protocol is the format of the messages: rfc5424 or rfc3164.
+	Protocol string
+	// OctetCounting frames the messages received over TCP with their length, as described in RFC 6587, instead of
+	// separating them with newlines. It requires the rfc5424 protocol.
+	OctetCounting bool
+}
+
+func (s SyslogSettings) validate() []error {
+	if s.OctetCounting && s.Protocol != "rfc5424" {
+		return []error{fmt.Errorf("syslog_octet_counting requires syslog_protocol rfc5424, not %s", s.Protocol)}
+	}
+	return nil
+}
+
+// FluentForwardSettings configures the receiver of the records of Fluentd and Fluent Bit, passing them to the logs
+// exporter.
+type FluentForwardSettings struct {
+	// Port is the port of the receiver, listening over TCP and answering heartbeats over UDP. The receiver is
+	// disabled when 0.
+	Port int
+	// Tag is the metadata of the events the tag of the records is mapped to: source or sourcetype.
+	Tag string
+}
+
+// StatsDSettings configures the receiver of StatsD metrics, aggregating them before passing them to the metrics
+// exporter.
+type StatsDSettings struct {
+	// Port is the UDP port of the receiver. The receiver is disabled when 0.
+	Port int
+	// AggregationInterval is the interval the metrics received are aggregated over.
+	AggregationInterval time.Duration
+	// Percentiles are the percentiles of the timings, histograms and distributions reported for each interval.
+	Percentiles []float64
+}
+
+// HECSettings configures the HTTP Event Collector endpoint events are forwarded to.
+type HECSettings struct {
+	// Endpoint is the URL of HEC, for example https://splunk:8088.
+	Endpoint string
+	// Token names the secret, stored in Splunk under PasswordRealm, holding the HEC token.
+	Token string
+	// UseAck waits for HEC to acknowledge events were indexed before dropping them from the queue.
+	UseAck bool
+	// CAFile verifies the certificate of HEC. The system roots are used when empty.
+	CAFile string
+}
+
+func (h HECSettings) validate() []error {
+	var errs []error
+	if h.Endpoint == "" {
+		errs = append(errs, errors.New("output_mode hec requires hec_endpoint"))
+	} else if u, err := url.Parse(h.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("hec_endpoint %q must be an http or https URL", h.Endpoint))
+	}
+	if h.Token == "" {
+		errs = append(errs, errors.New("output_mode hec requires hec_token"))
+	}
+	return errs
+}
+
+// QueueDirectory returns the directory of the persistent queue of the stanza. Every stanza of the input shares
+// the checkpoint directory, so each stanza gets its own subdirectory.
+func (s Settings) QueueDirectory() string {
+	return s.checkpointSubdirectory("queue")
+}
+
+// CaptureDirectory returns the directory the requests received by the stanza are captured to.
+func (s Settings) CaptureDirectory() string {
+	return s.checkpointSubdirectory("capture")
+}
+
+// checkpointSubdirectory returns the directory of the stanza under the dir directory of the checkpoint directory.
+func (s Settings) checkpointSubdirectory(dir string) string {
+	name := s.Name
+	if _, after, found := strings.Cut(name, "://"); found {
+		name = after
+	}
+	name = escapeDirectoryName(name)
+	if name == "" {
+		name = "default"
+	}
+	return filepath.Join(s.CheckpointDir, dir, name)
+}
+
+// escapeDirectoryName percent-encodes the bytes of a stanza name which are not letters, digits, _, . or -, so the
+// name can be used as a directory name on every platform, and distinct names never share a directory.
+func escapeDirectoryName(name string) string {
+	if name == "." || name == ".." {
+		return strings.ReplaceAll(name, ".", "%2E")
+	}
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '_', c == '.', c == '-':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// UnauthenticatedReceivers returns the parameters of the enabled receivers which cannot check the tokens of
+// AuthTokens, and accept data from any client. Syslog over TCP is left out when it is served with TLS.
+func (s Settings) UnauthenticatedReceivers() []string {
+	var params []string
+	if s.Syslog.TCPPort != 0 && !s.TLS.Enabled() {
+		params = append(params, "syslog_tcp_port")
+	}
+	if s.Syslog.UDPPort != 0 {
+		params = append(params, "syslog_udp_port")
+	}
+	if s.FluentForward.Port != 0 {
+		params = append(params, "fluentforward_port")
+	}
+	if s.StatsD.Port != 0 {
+		params = append(params, "statsd_port")
+	}
+	if s.GraphitePort != 0 {
+		params = append(params, "graphite_port")
+	}
+	return params
+}
+
+// checkUnauthenticated refuses the receivers which cannot check the tokens of AuthTokens, unless they are allowed.
+func (s Settings) checkUnauthenticated() []error {
+	if len(s.AuthTokens) == 0 || s.AllowUnauthenticated {
+		return nil
+	}
+	var errs []error
+	for _, param := range s.UnauthenticatedReceivers() {
+		errs = append(errs, fmt.Errorf("%s does not check auth_tokens, set allow_unauthenticated_receivers to accept its data from any client", param))
+	}
+	return errs
+}
+
+// checkCheckpointDir reports an error if the settings need a checkpoint directory Splunk did not provide.
+func (s Settings) checkCheckpointDir() error {
+	if s.CheckpointDir != "" {
+		return nil
+	}
+	var errs []error
+	if s.PersistentQueue {
+		errs = append(errs, errors.New("persistent_queue requires the checkpoint_dir Splunk provides to the input"))
+	}
+	if s.Capture.Enabled {
+		errs = append(errs, errors.New("capture requires the checkpoint_dir Splunk provides to the input"))
+	}
+	return errors.Join(errs...)
+}
+
+// TelemetrySettings configures the self telemetry of the input.
+type TelemetrySettings struct {
+	// Index is the metrics index the self telemetry is sent to.
+	Index string
+	// Interval is the period between two exports. Self telemetry is disabled when 0.
+	Interval time.Duration
+}
+
+// Listener is a port the input listens on.
+type Listener struct {
+	// Param is the name of the parameter setting the port.
+	Param string
+	// Network is either tcp or udp.
+	Network string
+	Port    int
+}
+
+// Listeners returns the ports the input listens on. The OTLP ports are always listed, optional listeners only
+// when they are enabled.
+func (s Settings) Listeners() []Listener {
+	listeners := []Listener{
+		{Param: "grpc_port", Network: "tcp", Port: s.GRPCPort},
+		{Param: "http_port", Network: "tcp", Port: s.HTTPPort},
+	}
+	if s.HealthPort != 0 {
+		listeners = append(listeners, Listener{Param: "health_port", Network: "tcp", Port: s.HealthPort})
+	}
+	if s.PrometheusRemoteWritePort != 0 {
+		listeners = append(listeners, Listener{Param: "prometheus_remote_write_port", Network: "tcp", Port: s.PrometheusRemoteWritePort})
+	}
+	if s.ZipkinPort != 0 {
+		listeners = append(listeners, Listener{Param: "zipkin_port", Network: "tcp", Port: s.ZipkinPort})
+	}
+	if s.JaegerGRPCPort != 0 {
+		listeners = append(listeners, Listener{Param: "jaeger_grpc_port", Network: "tcp", Port: s.JaegerGRPCPort})
+	}
+	if s.JaegerHTTPPort != 0 {
+		listeners = append(listeners, Listener{Param: "jaeger_http_port", Network: "tcp", Port: s.JaegerHTTPPort})
+	}
+	if s.HECPort != 0 {
+		listeners = append(listeners, Listener{Param: "hec_port", Network: "tcp", Port: s.HECPort})
+	}
+	if s.Syslog.TCPPort != 0 {
+		listeners = append(listeners, Listener{Param: "syslog_tcp_port", Network: "tcp", Port: s.Syslog.TCPPort})
+	}
+	if s.Syslog.UDPPort != 0 {
+		listeners = append(listeners, Listener{Param: "syslog_udp_port", Network: "udp", Port: s.Syslog.UDPPort})
+	}
+	if s.FluentForward.Port != 0 {
+		listeners = append(listeners,
+			Listener{Param: "fluentforward_port", Network: "tcp", Port: s.FluentForward.Port},
+			Listener{Param: "fluentforward_port", Network: "udp", Port: s.FluentForward.Port})
+	}
+	if s.StatsD.Port != 0 {
+		listeners = append(listeners, Listener{Param: "statsd_port", Network: "udp", Port: s.StatsD.Port})
+	}
+	if s.GraphitePort != 0 {
+		listeners = append(listeners, Listener{Param: "graphite_port", Network: "tcp", Port: s.GraphitePort})
+	}
+	return listeners
+}
+
+func (s Settings) checkPortCollisions() []error {
+	var errs []error
+	seen := map[Listener]string{}
+	for _, l := range s.Listeners() {
+		if l.Port == 0 {
+			continue
+		}
+		key := Listener{Network: l.Network, Port: l.Port}
+		if other, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("%s and %s must be different, both are set to %d", other, l.Param, l.Port))
+			continue
+		}
+		seen[key] = l.Param
+	}
+	return errs
+}
+
+// SignalSettings holds the default index and sourcetype of the events of a signal.
+type SignalSettings struct {
+	Index      string
+	SourceType string
+}
+
+// TLSSettings configures TLS on the OTLP listeners. Relative paths are resolved against the directory
+// of the app the stanza is defined in.
+type TLSSettings struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	MinVersion   string
+}
+
+// Enabled returns true if the OTLP listeners must serve TLS.
+func (t TLSSettings) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// MutualTLS returns true if clients must present a certificate signed by the client CA.
+func (t TLSSettings) MutualTLS() bool {
+	return t.ClientCAFile != ""
+}
+
+func (t TLSSettings) validate() []error {
+	var errs []error
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		errs = append(errs, errors.New("cert_file and key_file must be set together"))
+	}
+	if !t.Enabled() && (t.ClientCAFile != "" || t.MinVersion != "") {
+		errs = append(errs, errors.New("client_ca_file and min_version require cert_file and key_file"))
+	}
+	switch t.MinVersion {
+	case "", "1.0", "1.1", "1.2", "1.3":
+	default:
+		errs = append(errs, fmt.Errorf("min_version %q is not supported, it must be one of 1.0, 1.1, 1.2 or 1.3", t.MinVersion))
+	}
+	return errs
+}
+
+// splunkParams lists the parameters Splunk sets on every modular input stanza.
+// They are accepted but not interpreted by the input.
+var splunkParams = map[string]bool{
+	"disabled":                   true,
+	"interval":                   true,
+	"outputgroup":                true,
+	"persistentQueueSize":        true,
+	"python.required":            true,
+	"python.version":             true,
+	"queueSize":                  true,
+	"run_introspection":          true,
+	"sourcetype":                 true,
+	"start_by_shell":             true,
+	"_INDEX_AND_FORWARD_ROUTING": true,
+	"_meta":                      true,
+	"_rcvbuf":                    true,
+	"_SYSLOG_ROUTING":            true,
+	"_TCP_ROUTING":               true,
+}
+
+// decideOnStartup is the host value Splunk uses to ask the input to use the name of the server it runs on.
+const decideOnStartup = "$decideOnStartup"
+
+// Extract returns the settings of the input stanza, or an error listing every invalid or unknown parameter.
+func (x XMLInput) Extract() (Settings, error) {
+	settings, err := x.Configuration.Stanza.Settings()
+	settings.CheckpointDir = x.CheckpointDir
+	if cpErr := settings.checkCheckpointDir(); cpErr != nil {
+		err = errors.Join(err, cpErr)
+	}
+	if settings.Host == decideOnStartup {
+		settings.Host = x.ServerHost
+		if settings.Host == "" {
+			settings.Host, _ = os.Hostname()
+		}
+	}
+	if settings.Source == "" && x.SharedProcess {
+		// splunkd sets the source of the events of an input to its stanza name, but cannot tell apart the events
+		// of the stanzas sharing a process.
+		settings.Source = settings.Name
+	}
+	return settings, err
+}
+
+// Settings returns the settings of the stanza, or an error listing every invalid or unknown parameter.
+func (s XMLStanza) Settings() (Settings, error) {
+	settings := Settings{
+		Name:                 s.Name,
+		ListenAddress:        DefaultListenAddress,
+		GRPCPort:             DefaultGrpcPort,
+		HTTPPort:             DefaultHTTPPort,
+		IndexViolationAction: DefaultIndexViolationAction,
+		OutputMode:           DefaultOutputMode,
+		Telemetry: TelemetrySettings{
+			Index:    DefaultTelemetryIndex,
+			Interval: DefaultTelemetryInterval,
+		},
+		Syslog: SyslogSettings{
+			Protocol: DefaultSyslogProtocol,
+		},
+		FluentForward: FluentForwardSettings{
+			Tag: DefaultFluentForwardTag,
+		},
+		StatsD: StatsDSettings{
+			AggregationInterval: DefaultStatsDAggregationInterval,
+			Percentiles:         []float64{50, 90, 95, 99},
+		},
+		Capture: CaptureSettings{
+			MaxSize:  DefaultCaptureMaxSize,
+			MaxFiles: DefaultCaptureMaxFiles,
+		},
+		Log: LogSettings{
+			Level:  zapcore.InfoLevel,
+			Format: DefaultLogFormat,
+		},
+		ShutdownTimeout: DefaultShutdownTimeout,
+	}
 
-	for _, p := range x.Configuration.Stanza.Params {
+	var errs []error
+	for _, p := range s.Params {
+		var err error
 		switch p.Name {
 		case "grpc_port":
-			grpcPort, _ = strconv.Atoi(p.Value)
+			settings.GRPCPort, err = parseRequiredPort(p)
 		case "http_port":
-			httpPort, _ = strconv.Atoi(p.Value)
+			settings.HTTPPort, err = parseRequiredPort(p)
+		case "health_port":
+			settings.HealthPort, err = parsePort(p)
+		case "prometheus_remote_write_port":
+			settings.PrometheusRemoteWritePort, err = parsePort(p)
+		case "zipkin_port":
+			settings.ZipkinPort, err = parsePort(p)
+		case "jaeger_grpc_port":
+			settings.JaegerGRPCPort, err = parsePort(p)
+		case "jaeger_http_port":
+			settings.JaegerHTTPPort, err = parsePort(p)
+		case "hec_port":
+			settings.HECPort, err = parsePort(p)
+		case "syslog_tcp_port":
+			settings.Syslog.TCPPort, err = parsePort(p)
+		case "syslog_udp_port":
+			settings.Syslog.UDPPort, err = parsePort(p)
+		case "syslog_protocol":
+			switch protocol := strings.ToLower(strings.TrimSpace(p.Value)); protocol {
+			case "rfc5424", "rfc3164":
+				settings.Syslog.Protocol = protocol
+			default:
+				err = fmt.Errorf("syslog_protocol %q is not supported, it must be either rfc5424 or rfc3164", p.Value)
+			}
+		case "syslog_octet_counting":
+			settings.Syslog.OctetCounting, err = parseBool(p)
+		case "fluentforward_port":
+			settings.FluentForward.Port, err = parsePort(p)
+		case "fluentforward_tag":
+			switch tag := strings.TrimSpace(p.Value); tag {
+			case "source", "sourcetype":
+				settings.FluentForward.Tag = tag
+			default:
+				err = fmt.Errorf("fluentforward_tag %q is not supported, it must be either source or sourcetype", p.Value)
+			}
+		case "statsd_port":
+			settings.StatsD.Port, err = parsePort(p)
+		case "statsd_aggregation_interval":
+			var seconds int
+			if seconds, err = parsePositive(p); err == nil {
+				settings.StatsD.AggregationInterval = time.Duration(seconds) * time.Second
+			}
+		case "statsd_percentiles":
+			settings.StatsD.Percentiles, err = parsePercentiles(p)
+		case "graphite_port":
+			settings.GraphitePort, err = parsePort(p)
 		case "listen_address":
-			listeningAddress = p.Value
+			settings.ListenAddress = p.Value
+			if net.ParseIP(p.Value) == nil {
+				err = fmt.Errorf("listen_address %q is not a valid IP address", p.Value)
+			}
+		case "cert_file":
+			settings.TLS.CertFile = s.resolvePath(p.Value)
+		case "key_file":
+			settings.TLS.KeyFile = s.resolvePath(p.Value)
+		case "client_ca_file":
+			settings.TLS.ClientCAFile = s.resolvePath(p.Value)
+		case "min_version":
+			settings.TLS.MinVersion = strings.TrimSpace(p.Value)
+		case "index":
+			settings.Index = strings.TrimSpace(p.Value)
+			if settings.Index == "default" {
+				settings.Index = ""
+			}
+		case "source":
+			settings.Source = strings.TrimSpace(p.Value)
+		case "host":
+			settings.Host = strings.TrimSpace(p.Value)
+		case "logs_index":
+			settings.Logs.Index = strings.TrimSpace(p.Value)
+		case "logs_sourcetype":
+			settings.Logs.SourceType = strings.TrimSpace(p.Value)
+		case "traces_index":
+			settings.Traces.Index = strings.TrimSpace(p.Value)
+		case "traces_sourcetype":
+			settings.Traces.SourceType = strings.TrimSpace(p.Value)
+		case "metrics_index":
+			settings.Metrics.Index = strings.TrimSpace(p.Value)
+		case "metrics_sourcetype":
+			settings.Metrics.SourceType = strings.TrimSpace(p.Value)
+		case "allowed_indexes":
+			settings.AllowedIndexes = splitList(p.Value)
+			for _, expr := range settings.AllowedIndexes {
+				if _, reErr := regexp.Compile(expr); reErr != nil {
+					errs = append(errs, fmt.Errorf("allowed_indexes entry %q is not a valid regular expression: %w", expr, reErr))
+				}
+			}
+		case "index_violation_action":
+			settings.IndexViolationAction = strings.TrimSpace(p.Value)
+			switch settings.IndexViolationAction {
+			case "drop", "default", "reject":
+			default:
+				err = fmt.Errorf("index_violation_action %q is not supported, it must be one of drop, default or reject", p.Value)
+			}
+		case "auth_tokens":
+			settings.AuthTokens = splitList(p.Value)
+		case "allow_unauthenticated_receivers":
+			settings.AllowUnauthenticated, err = parseBool(p)
+		case "telemetry_index":
+			settings.Telemetry.Index = strings.TrimSpace(p.Value)
+		case "telemetry_interval":
+			settings.Telemetry.Interval, err = parseInterval(p)
+		case "persistent_queue":
+			settings.PersistentQueue, err = parseBool(p)
+		case "output_mode":
+			settings.OutputMode = strings.TrimSpace(p.Value)
+			switch settings.OutputMode {
+			case "stdout", "hec":
+			default:
+				err = fmt.Errorf("output_mode %q is not supported, it must be either stdout or hec", p.Value)
+			}
+		case "hec_endpoint":
+			settings.HEC.Endpoint = strings.TrimSpace(p.Value)
+		case "hec_token":
+			settings.HEC.Token = strings.TrimSpace(p.Value)
+		case "hec_use_ack":
+			settings.HEC.UseAck, err = parseBool(p)
+		case "hec_ca_file":
+			settings.HEC.CAFile = s.resolvePath(p.Value)
+		case "capture":
+			settings.Capture.Enabled, err = parseBool(p)
+		case "capture_max_size":
+			settings.Capture.MaxSize, err = parsePositive(p)
+		case "capture_max_files":
+			settings.Capture.MaxFiles, err = parsePositive(p)
+		case "shutdown_timeout":
+			var seconds int
+			if seconds, err = parsePositive(p); err == nil {
+				settings.ShutdownTimeout = time.Duration(seconds) * time.Second
+			}
+		case "reload_interval":
+			settings.ReloadInterval, err = parseInterval(p)
+		case "log_level":
+			switch level := strings.ToLower(strings.TrimSpace(p.Value)); level {
+			case "debug", "info", "warn", "error":
+				settings.Log.Level, _ = zapcore.ParseLevel(level)
+			default:
+				err = fmt.Errorf("log_level %q is not supported, it must be one of debug, info, warn or error", p.Value)
+			}
+		case "log_format":
+			switch format := strings.TrimSpace(p.Value); format {
+			case "json", splunkdEncoding:
+				settings.Log.Format = format
+			default:
+				err = fmt.Errorf("log_format %q is not supported, it must be either json or splunkd", p.Value)
+			}
+		default:
+			if !splunkParams[p.Name] {
+				err = fmt.Errorf("unknown parameter %q", p.Name)
+			}
 		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, signal := range []*SignalSettings{&settings.Logs, &settings.Traces, &settings.Metrics} {
+		if signal.Index == "" {
+			signal.Index = settings.Index
+		}
+	}
+	errs = append(errs, settings.TLS.validate()...)
+	errs = append(errs, settings.Syslog.validate()...)
+	if settings.OutputMode == "hec" {
+		errs = append(errs, settings.HEC.validate()...)
+	}
+	errs = append(errs, settings.checkUnauthenticated()...)
+	if len(errs) == 0 {
+		errs = settings.checkPortCollisions()
+	}
+
+	return settings, errors.Join(errs...)
+}
+
+// resolvePath resolves a relative path against the directory of the app the stanza is defined in,
+// so files can ship inside a deployment app.
+func (s XMLStanza) resolvePath(path string) string {
+	path = strings.TrimSpace(path)
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	splunkHome := os.Getenv("SPLUNK_HOME")
+	if splunkHome == "" || s.App == "" {
+		return path
+	}
+	return filepath.Join(splunkHome, "etc", "apps", s.App, path)
+}
+
+// splitList splits a comma-separated parameter value, ignoring empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parsePort(p XMLParam) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(p.Value))
+	if err != nil {
+		return 0, fmt.Errorf("%s %q is not a number", p.Name, p.Value)
+	}
+	if port < 0 || port > 65535 {
+		return 0, fmt.Errorf("%s %d is out of range, it must be between 0 and 65535", p.Name, port)
 	}
+	return port, nil
+}
+
+// parseRequiredPort parses the port of a listener which cannot be disabled, so 0 is refused.
+func parseRequiredPort(p XMLParam) (int, error) {
+	port, err := parsePort(p)
+	if err == nil && port == 0 {
+		err = fmt.Errorf("%s must be set to a port between 1 and 65535", p.Name)
+	}
+	return port, err
+}
+
+// parseBool parses a boolean the way Splunk writes them in configuration files.
+func parseBool(p XMLParam) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(p.Value)) {
+	case "1", "true", "t", "yes", "y", "on":
+		return true, nil
+	case "", "0", "false", "f", "no", "n", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("%s %q is not a boolean", p.Name, p.Value)
+}
+
+// parseInterval parses a number of seconds.
+func parseInterval(p XMLParam) (time.Duration, error) {
+	seconds, err := strconv.Atoi(strings.TrimSpace(p.Value))
+	if err != nil {
+		return 0, fmt.Errorf("%s %q is not a number", p.Name, p.Value)
+	}
+	if seconds < 0 {
+		return 0, fmt.Errorf("%s %d must not be negative", p.Name, seconds)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
 
-	return grpcPort, httpPort, listeningAddress
+// parsePercentiles parses a comma-separated list of percentiles, between 0 and 100.
+func parsePercentiles(p XMLParam) ([]float64, error) {
+	var percentiles []float64
+	for _, item := range splitList(p.Value) {
+		percentile, err := strconv.ParseFloat(item, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s entry %q is not a number", p.Name, item)
+		}
+		if percentile < 0 || percentile > 100 {
+			return nil, fmt.Errorf("%s entry %s is out of range, it must be between 0 and 100", p.Name, item)
+		}
+		percentiles = append(percentiles, percentile)
+	}
+	return percentiles, nil
+}
+
+// parsePositive parses a number greater than 0.
+func parsePositive(p XMLParam) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(p.Value))
+	if err != nil {
+		return 0, fmt.Errorf("%s %q is not a number", p.Name, p.Value)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("%s %d must be greater than 0", p.Name, n)
+	}
+	return n, nil
 }
 
 func ReadFromStdin() (XMLInput, error) {
@@ -5,9 +5,12 @@ package internal
 
 import (
 	"encoding/xml"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 )
 
 func TestParseInput(t *testing.T) {
@@ -38,9 +41,532 @@ func TestParseInput(t *testing.T) {
 	require.Equal(t, "splunk-connect-for-otlp://specialmind", config.Configuration.Stanza.Name)
 	require.Equal(t, "main", config.Configuration.Stanza.Params[3].Value)
 
-	grpcPort, httpPort, listeningAddress := config.Extract()
+	settings, err := config.Extract()
+	require.NoError(t, err)
+
+	require.Equal(t, 4317, settings.GRPCPort)
+	require.Equal(t, "0.0.0.0", settings.ListenAddress)
+	require.Equal(t, 4318, settings.HTTPPort)
+	require.Equal(t, "main", settings.Index)
+	require.Empty(t, settings.Logs.SourceType)
+	require.Equal(t, "773c28971b2a", settings.Host)
+}
+
+func TestParseInputStanzas(t *testing.T) {
+	input := `
+<input>
+  <server_host>773c28971b2a</server_host>
+  <checkpoint_dir>/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp</checkpoint_dir>
+  <configuration>
+    <stanza name="splunk-connect-for-otlp://team_a" app="search">
+      <param name="http_port">4318</param>
+      <param name="index">team_a</param>
+    </stanza>
+    <stanza name="splunk-connect-for-otlp://team_b" app="otlp_b">
+      <param name="http_port">5318</param>
+      <param name="source">otlp:team_b</param>
+    </stanza>
+  </configuration>
+</input>`
+
+	var config XMLInput
+	require.NoError(t, xml.Unmarshal([]byte(input), &config))
+	require.Len(t, config.Configuration.Stanzas, 2)
+	require.Equal(t, "splunk-connect-for-otlp://team_a", config.Configuration.Stanza.Name)
+
+	inputs := config.Inputs()
+	require.Len(t, inputs, 2)
+	a, err := inputs[0].Extract()
+	require.NoError(t, err)
+	require.Equal(t, "team_a", a.Index)
+	require.Equal(t, 4318, a.HTTPPort)
+	// The stanzas share the process, so the events of each stanza get its name as their default source.
+	require.Equal(t, "splunk-connect-for-otlp://team_a", a.Source)
+	require.Equal(t, "/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp", a.CheckpointDir)
+
+	b, err := inputs[1].Extract()
+	require.NoError(t, err)
+	require.Equal(t, "otlp_b", inputs[1].Configuration.Stanza.App)
+	require.Equal(t, 5318, b.HTTPPort)
+	require.Equal(t, "otlp:team_b", b.Source)
+	require.Equal(t, "773c28971b2a", inputs[1].ServerHost)
+
+	config.Configuration.Stanzas = config.Configuration.Stanzas[:1]
+	single, err := config.Inputs()[0].Extract()
+	require.NoError(t, err)
+	require.Empty(t, single.Source)
+}
+
+func TestExtractReportsEveryInvalidParam(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "grpc_port", Value: "abc"},
+		{Name: "http_port", Value: "-1"},
+		{Name: "listen_address", Value: "0.0.0"},
+		{Name: "index", Value: "main"},
+		{Name: "output_mode", Value: "file"},
+		{Name: "grcp_port", Value: "4317"},
+	}}}}
+
+	_, err := config.Extract()
+	require.EqualError(t, err, `grpc_port "abc" is not a number
+http_port -1 is out of range, it must be between 0 and 65535
+listen_address "0.0.0" is not a valid IP address
+output_mode "file" is not supported, it must be either stdout or hec
+unknown parameter "grcp_port"`)
+}
+
+func TestExtractDefaults(t *testing.T) {
+	settings, err := XMLInput{}.Extract()
+	require.NoError(t, err)
+	require.Equal(t, Settings{
+		ListenAddress:        DefaultListenAddress,
+		GRPCPort:             DefaultGrpcPort,
+		HTTPPort:             DefaultHTTPPort,
+		IndexViolationAction: DefaultIndexViolationAction,
+		OutputMode:           DefaultOutputMode,
+		Telemetry: TelemetrySettings{
+			Index:    DefaultTelemetryIndex,
+			Interval: DefaultTelemetryInterval,
+		},
+		Syslog: SyslogSettings{
+			Protocol: DefaultSyslogProtocol,
+		},
+		FluentForward: FluentForwardSettings{
+			Tag: DefaultFluentForwardTag,
+		},
+		StatsD: StatsDSettings{
+			AggregationInterval: DefaultStatsDAggregationInterval,
+			Percentiles:         []float64{50, 90, 95, 99},
+		},
+		Capture: CaptureSettings{
+			MaxSize:  DefaultCaptureMaxSize,
+			MaxFiles: DefaultCaptureMaxFiles,
+		},
+		Log: LogSettings{
+			Level:  zapcore.InfoLevel,
+			Format: DefaultLogFormat,
+		},
+		ShutdownTimeout: DefaultShutdownTimeout,
+	}, settings)
+}
+
+func TestExtractTLS(t *testing.T) {
+	t.Setenv("SPLUNK_HOME", "/opt/splunk")
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{App: "otlp_deployment", Params: []XMLParam{
+		{Name: "cert_file", Value: "certs/server.pem"},
+		{Name: "key_file", Value: "/etc/ssl/server.key"},
+		{Name: "client_ca_file", Value: "certs/ca.pem"},
+		{Name: "min_version", Value: "1.3"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, TLSSettings{
+		CertFile:     filepath.Join("/opt/splunk", "etc", "apps", "otlp_deployment", "certs", "server.pem"),
+		KeyFile:      "/etc/ssl/server.key",
+		ClientCAFile: filepath.Join("/opt/splunk", "etc", "apps", "otlp_deployment", "certs", "ca.pem"),
+		MinVersion:   "1.3",
+	}, settings.TLS)
+	require.True(t, settings.TLS.Enabled())
+	require.True(t, settings.TLS.MutualTLS())
+}
+
+func TestExtractInvalidTLS(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "key_file", Value: "server.key"},
+		{Name: "min_version", Value: "1.4"},
+	}}}}
+
+	_, err := config.Extract()
+	require.EqualError(t, err, `cert_file and key_file must be set together
+client_ca_file and min_version require cert_file and key_file
+min_version "1.4" is not supported, it must be one of 1.0, 1.1, 1.2 or 1.3`)
+}
+
+func TestExtractSignalDefaults(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "index", Value: "main"},
+		{Name: "sourcetype", Value: "_splunk-connect-for-otlp"},
+		{Name: "logs_sourcetype", Value: "otlp:logs"},
+		{Name: "traces_index", Value: "traces"},
+		{Name: "metrics_index", Value: "metrics"},
+		{Name: "metrics_sourcetype", Value: "otlp:metrics"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, SignalSettings{Index: "main", SourceType: "otlp:logs"}, settings.Logs)
+	require.Equal(t, SignalSettings{Index: "traces"}, settings.Traces)
+	require.Equal(t, SignalSettings{Index: "metrics", SourceType: "otlp:metrics"}, settings.Metrics)
+}
+
+func TestExtractIndexPolicy(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "allowed_indexes", Value: "main, otlp_.*,"},
+		{Name: "index_violation_action", Value: "reject"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, []string{"main", "otlp_.*"}, settings.AllowedIndexes)
+	require.Equal(t, "reject", settings.IndexViolationAction)
+
+	config.Configuration.Stanza.Params = []XMLParam{
+		{Name: "allowed_indexes", Value: "otlp_("},
+		{Name: "index_violation_action", Value: "ignore"},
+	}
+	_, err = config.Extract()
+	require.ErrorContains(t, err, `allowed_indexes entry "otlp_(" is not a valid regular expression`)
+	require.ErrorContains(t, err, `index_violation_action "ignore" is not supported, it must be one of drop, default or reject`)
+}
+
+func TestExtractTelemetry(t *testing.T) {
+	settings, err := XMLInput{}.Extract()
+	require.NoError(t, err)
+	require.Equal(t, TelemetrySettings{Index: "_metrics", Interval: time.Minute}, settings.Telemetry)
+
+	settings, err = XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "telemetry_interval", Value: "0"},
+	}}}}.Extract()
+	require.NoError(t, err)
+	require.Zero(t, settings.Telemetry.Interval)
+
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "telemetry_index", Value: "otlp_internal"},
+		{Name: "telemetry_interval", Value: "10"},
+	}}}}
+
+	settings, err = config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, TelemetrySettings{Index: "otlp_internal", Interval: 10 * time.Second}, settings.Telemetry)
+
+	config.Configuration.Stanza.Params[1].Value = "-1"
+	_, err = config.Extract()
+	require.EqualError(t, err, "telemetry_interval -1 must not be negative")
+}
+
+func TestExtractShutdownTimeout(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "shutdown_timeout", Value: "45"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, 45*time.Second, settings.ShutdownTimeout)
+
+	config.Configuration.Stanza.Params[0].Value = "0"
+	_, err = config.Extract()
+	require.EqualError(t, err, "shutdown_timeout 0 must be greater than 0")
+}
+
+func TestExtractReloadInterval(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "reload_interval", Value: "30"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, settings.ReloadInterval)
+
+	config.Configuration.Stanza.Params = nil
+	settings, err = config.Extract()
+	require.NoError(t, err)
+	require.Zero(t, settings.ReloadInterval)
+}
+
+func TestExtractPrometheusRemoteWritePort(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "prometheus_remote_write_port", Value: "9090"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, 9090, settings.PrometheusRemoteWritePort)
+	require.Contains(t, settings.Listeners(), Listener{Param: "prometheus_remote_write_port", Network: "tcp", Port: 9090})
+
+	config.Configuration.Stanza.Params[0].Value = "4318"
+	_, err = config.Extract()
+	require.EqualError(t, err, "http_port and prometheus_remote_write_port must be different, both are set to 4318")
+}
+
+func TestExtractTraceReceiverPorts(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "zipkin_port", Value: "9411"},
+		{Name: "jaeger_grpc_port", Value: "14250"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, 9411, settings.ZipkinPort)
+	require.Equal(t, 14250, settings.JaegerGRPCPort)
+	require.Zero(t, settings.JaegerHTTPPort)
+	require.Equal(t, []Listener{
+		{Param: "grpc_port", Network: "tcp", Port: DefaultGrpcPort},
+		{Param: "http_port", Network: "tcp", Port: DefaultHTTPPort},
+		{Param: "zipkin_port", Network: "tcp", Port: 9411},
+		{Param: "jaeger_grpc_port", Network: "tcp", Port: 14250},
+	}, settings.Listeners())
+
+	config.Configuration.Stanza.Params = append(config.Configuration.Stanza.Params, XMLParam{Name: "jaeger_http_port", Value: "9411"})
+	_, err = config.Extract()
+	require.EqualError(t, err, "zipkin_port and jaeger_http_port must be different, both are set to 9411")
+}
+
+func TestExtractHECPort(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "hec_port", Value: "8088"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, 8088, settings.HECPort)
+	require.Equal(t, []Listener{
+		{Param: "grpc_port", Network: "tcp", Port: DefaultGrpcPort},
+		{Param: "http_port", Network: "tcp", Port: DefaultHTTPPort},
+		{Param: "hec_port", Network: "tcp", Port: 8088},
+	}, settings.Listeners())
+
+	config.Configuration.Stanza.Params = append(config.Configuration.Stanza.Params, XMLParam{Name: "http_port", Value: "8088"})
+	_, err = config.Extract()
+	require.EqualError(t, err, "http_port and hec_port must be different, both are set to 8088")
+}
+
+func TestExtractSyslog(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "syslog_tcp_port", Value: "5514"},
+		{Name: "syslog_udp_port", Value: "5514"},
+		{Name: "syslog_octet_counting", Value: "1"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, SyslogSettings{TCPPort: 5514, UDPPort: 5514, Protocol: "rfc5424", OctetCounting: true}, settings.Syslog)
+	require.Equal(t, []Listener{
+		{Param: "grpc_port", Network: "tcp", Port: DefaultGrpcPort},
+		{Param: "http_port", Network: "tcp", Port: DefaultHTTPPort},
+		{Param: "syslog_tcp_port", Network: "tcp", Port: 5514},
+		{Param: "syslog_udp_port", Network: "udp", Port: 5514},
+	}, settings.Listeners())
+
+	config.Configuration.Stanza.Params = append(config.Configuration.Stanza.Params, XMLParam{Name: "syslog_protocol", Value: "RFC3164"})
+	_, err = config.Extract()
+	require.EqualError(t, err, "syslog_octet_counting requires syslog_protocol rfc5424, not rfc3164")
+
+	config.Configuration.Stanza.Params[3].Value = "rfc6587"
+	_, err = config.Extract()
+	require.EqualError(t, err, `syslog_protocol "rfc6587" is not supported, it must be either rfc5424 or rfc3164`)
+
+	// Unlike the OTLP ports, the ports of optional receivers are disabled with 0.
+	config.Configuration.Stanza.Params = []XMLParam{{Name: "syslog_tcp_port", Value: "0"}, {Name: "grpc_port", Value: "0"}}
+	settings, err = config.Extract()
+	require.EqualError(t, err, "grpc_port must be set to a port between 1 and 65535")
+	require.Zero(t, settings.Syslog.TCPPort)
+}
+
+func TestExtractFluentForward(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "fluentforward_port", Value: "24224"},
+		{Name: "fluentforward_tag", Value: "sourcetype"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, FluentForwardSettings{Port: 24224, Tag: "sourcetype"}, settings.FluentForward)
+	require.Equal(t, []Listener{
+		{Param: "grpc_port", Network: "tcp", Port: DefaultGrpcPort},
+		{Param: "http_port", Network: "tcp", Port: DefaultHTTPPort},
+		{Param: "fluentforward_port", Network: "tcp", Port: 24224},
+		{Param: "fluentforward_port", Network: "udp", Port: 24224},
+	}, settings.Listeners())
+
+	// Heartbeats are answered over UDP on the same port.
+	config.Configuration.Stanza.Params = append(config.Configuration.Stanza.Params, XMLParam{Name: "syslog_udp_port", Value: "24224"})
+	_, err = config.Extract()
+	require.EqualError(t, err, "syslog_udp_port and fluentforward_port must be different, both are set to 24224")
+
+	config.Configuration.Stanza.Params = config.Configuration.Stanza.Params[:2]
+	config.Configuration.Stanza.Params[1].Value = "index"
+	_, err = config.Extract()
+	require.EqualError(t, err, `fluentforward_tag "index" is not supported, it must be either source or sourcetype`)
+}
+
+func TestExtractUnauthenticatedReceivers(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "syslog_tcp_port", Value: "5514"},
+		{Name: "syslog_udp_port", Value: "5514"},
+		{Name: "fluentforward_port", Value: "24224"},
+		{Name: "statsd_port", Value: "8125"},
+		{Name: "graphite_port", Value: "2003"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, []string{"syslog_tcp_port", "syslog_udp_port", "fluentforward_port", "statsd_port", "graphite_port"},
+		settings.UnauthenticatedReceivers())
+
+	config.Configuration.Stanza.Params = append(config.Configuration.Stanza.Params, XMLParam{Name: "auth_tokens", Value: "team_a"})
+	_, err = config.Extract()
+	require.EqualError(t, err, `syslog_tcp_port does not check auth_tokens, set allow_unauthenticated_receivers to accept its data from any client
+syslog_udp_port does not check auth_tokens, set allow_unauthenticated_receivers to accept its data from any client
+fluentforward_port does not check auth_tokens, set allow_unauthenticated_receivers to accept its data from any client
+statsd_port does not check auth_tokens, set allow_unauthenticated_receivers to accept its data from any client
+graphite_port does not check auth_tokens, set allow_unauthenticated_receivers to accept its data from any client`)
+
+	config.Configuration.Stanza.Params = append(config.Configuration.Stanza.Params, XMLParam{Name: "allow_unauthenticated_receivers", Value: "1"})
+	settings, err = config.Extract()
+	require.NoError(t, err)
+	require.True(t, settings.AllowUnauthenticated)
+
+	// Syslog over TCP is served with the TLS settings of the OTLP receiver.
+	config = XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "syslog_tcp_port", Value: "5514"},
+		{Name: "cert_file", Value: "/tls/cert.pem"},
+		{Name: "key_file", Value: "/tls/key.pem"},
+		{Name: "auth_tokens", Value: "team_a"},
+	}}}}
+	settings, err = config.Extract()
+	require.NoError(t, err)
+	require.Empty(t, settings.UnauthenticatedReceivers())
+}
+
+func TestExtractStatsDAndGraphite(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "statsd_port", Value: "8125"},
+		{Name: "statsd_aggregation_interval", Value: "10"},
+		{Name: "statsd_percentiles", Value: "50, 99.9"},
+		{Name: "graphite_port", Value: "2003"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, StatsDSettings{Port: 8125, AggregationInterval: 10 * time.Second, Percentiles: []float64{50, 99.9}}, settings.StatsD)
+	require.Equal(t, 2003, settings.GraphitePort)
+	require.Equal(t, []Listener{
+		{Param: "grpc_port", Network: "tcp", Port: DefaultGrpcPort},
+		{Param: "http_port", Network: "tcp", Port: DefaultHTTPPort},
+		{Param: "statsd_port", Network: "udp", Port: 8125},
+		{Param: "graphite_port", Network: "tcp", Port: 2003},
+	}, settings.Listeners())
+
+	config.Configuration.Stanza.Params[1].Value = "0"
+	config.Configuration.Stanza.Params[2].Value = "50,101"
+	_, err = config.Extract()
+	require.EqualError(t, err, `statsd_aggregation_interval 0 must be greater than 0
+statsd_percentiles entry 101 is out of range, it must be between 0 and 100`)
+}
+
+func TestExtractPersistentQueue(t *testing.T) {
+	config := XMLInput{
+		CheckpointDir: "/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp",
+		Configuration: XMLConfig{Stanza: XMLStanza{Name: "splunk-connect-for-otlp://team a", Params: []XMLParam{
+			{Name: "persistent_queue", Value: "1"},
+		}}},
+	}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.True(t, settings.PersistentQueue)
+	require.Equal(t, filepath.Join("/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp", "queue", "team%20a"), settings.QueueDirectory())
+
+	// Stanzas whose names only differ by characters which cannot be used in a directory name get their own queue.
+	directories := map[string]string{}
+	for _, name := range []string{"team a", "team/a", "team_a", "team%20a", "team.a", "..", ""} {
+		settings.Name = StanzaScheme + name
+		directories[settings.QueueDirectory()] = name
+	}
+	require.Equal(t, map[string]string{
+		filepath.Join("/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp", "queue", "team%20a"):   "team a",
+		filepath.Join("/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp", "queue", "team%2Fa"):   "team/a",
+		filepath.Join("/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp", "queue", "team_a"):     "team_a",
+		filepath.Join("/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp", "queue", "team%2520a"): "team%20a",
+		filepath.Join("/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp", "queue", "team.a"):     "team.a",
+		filepath.Join("/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp", "queue", "%2E%2E"):     "..",
+		filepath.Join("/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp", "queue", "default"):    "",
+	}, directories)
+
+	config.CheckpointDir = ""
+	_, err = config.Extract()
+	require.EqualError(t, err, "persistent_queue requires the checkpoint_dir Splunk provides to the input")
+
+	config.Configuration.Stanza.Params[0].Value = "sometimes"
+	_, err = config.Extract()
+	require.EqualError(t, err, `persistent_queue "sometimes" is not a boolean`)
+}
+
+func TestExtractCapture(t *testing.T) {
+	config := XMLInput{
+		CheckpointDir: "/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp",
+		Configuration: XMLConfig{Stanza: XMLStanza{Name: "splunk-connect-for-otlp://team a", Params: []XMLParam{
+			{Name: "capture", Value: "1"},
+			{Name: "capture_max_size", Value: "5"},
+			{Name: "capture_max_files", Value: "3"},
+		}}},
+	}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, CaptureSettings{Enabled: true, MaxSize: 5, MaxFiles: 3}, settings.Capture)
+	require.Equal(t, filepath.Join("/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp", "capture", "team%20a"), settings.CaptureDirectory())
+
+	config.CheckpointDir = ""
+	_, err = config.Extract()
+	require.EqualError(t, err, "capture requires the checkpoint_dir Splunk provides to the input")
+
+	config.CheckpointDir = "/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp"
+	config.Configuration.Stanza.Params[1].Value = "0"
+	config.Configuration.Stanza.Params[2].Value = "many"
+	_, err = config.Extract()
+	require.EqualError(t, err, `capture_max_size 0 must be greater than 0
+capture_max_files "many" is not a number`)
+}
+
+func TestExtractLog(t *testing.T) {
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{Params: []XMLParam{
+		{Name: "log_level", Value: "DEBUG"},
+		{Name: "log_format", Value: "splunkd"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, LogSettings{Level: zapcore.DebugLevel, Format: "splunkd"}, settings.Log)
+
+	config.Configuration.Stanza.Params[0].Value = "trace"
+	config.Configuration.Stanza.Params[1].Value = "text"
+	settings, err = config.Extract()
+	require.EqualError(t, err, `log_level "trace" is not supported, it must be one of debug, info, warn or error
+log_format "text" is not supported, it must be either json or splunkd`)
+	require.Equal(t, zapcore.InfoLevel, settings.Log.Level, "invalid settings must leave the defaults")
+}
+
+func TestExtractHECOutput(t *testing.T) {
+	t.Setenv("SPLUNK_HOME", "/opt/splunk")
+	config := XMLInput{Configuration: XMLConfig{Stanza: XMLStanza{App: "otlp_deployment", Params: []XMLParam{
+		{Name: "output_mode", Value: "hec"},
+		{Name: "hec_endpoint", Value: "https://splunk-idx:8088"},
+		{Name: "hec_token", Value: "edge"},
+		{Name: "hec_use_ack", Value: "true"},
+		{Name: "hec_ca_file", Value: "certs/ca.pem"},
+	}}}}
+
+	settings, err := config.Extract()
+	require.NoError(t, err)
+	require.Equal(t, "hec", settings.OutputMode)
+	require.Equal(t, HECSettings{
+		Endpoint: "https://splunk-idx:8088",
+		Token:    "edge",
+		UseAck:   true,
+		CAFile:   filepath.Join("/opt/splunk", "etc", "apps", "otlp_deployment", "certs/ca.pem"),
+	}, settings.HEC)
+
+	config.Configuration.Stanza.Params = []XMLParam{
+		{Name: "output_mode", Value: "hec"},
+		{Name: "hec_endpoint", Value: "splunk-idx:8088"},
+	}
+	_, err = config.Extract()
+	require.EqualError(t, err, `hec_endpoint "splunk-idx:8088" must be an http or https URL
+output_mode hec requires hec_token`)
 
-	require.Equal(t, 4317, grpcPort)
-	require.Equal(t, "0.0.0.0", listeningAddress)
-	require.Equal(t, 4318, httpPort)
+	config.Configuration.Stanza.Params = []XMLParam{{Name: "output_mode", Value: "kafka"}}
+	_, err = config.Extract()
+	require.EqualError(t, err, `output_mode "kafka" is not supported, it must be either stdout or hec`)
 }
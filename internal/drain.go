@@ -0,0 +1,137 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"strings"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+const (
+	acceptedPrefix   = "otelcol_receiver_accepted_"
+	sentPrefix       = "otelcol_exporter_sent_"
+	sendFailedPrefix = "otelcol_exporter_send_failed_"
+	queueSizeMetric  = "otelcol_exporter_queue_size"
+)
+
+// SignalCounts sums the items of a signal the receivers of an input accepted, and its exporters sent and failed
+// to send, since they were created.
+type SignalCounts struct {
+	Accepted int64
+	Sent     int64
+	Failed   int64
+}
+
+// Discarded returns the items accepted but neither sent nor failed, which are left in the exporter queue.
+func (c SignalCounts) Discarded() int64 {
+	return max(c.Accepted-c.Sent-c.Failed, 0)
+}
+
+// ExporterCounts holds the counts of each signal of an input, and the requests left in its exporter queues.
+type ExporterCounts struct {
+	Logs    SignalCounts
+	Traces  SignalCounts
+	Metrics SignalCounts
+	Queued  int64
+}
+
+// Sent returns the items of all signals the exporters sent.
+func (c ExporterCounts) Sent() int64 {
+	return c.Logs.Sent + c.Traces.Sent + c.Metrics.Sent
+}
+
+// Failed returns the items of all signals the exporters failed to send.
+func (c ExporterCounts) Failed() int64 {
+	return c.Logs.Failed + c.Traces.Failed + c.Metrics.Failed
+}
+
+// Sub returns the items sent and failed to send since before. Accepted and Queued are left unchanged.
+func (c ExporterCounts) Sub(before ExporterCounts) ExporterCounts {
+	sub := func(c, before SignalCounts) SignalCounts {
+		return SignalCounts{Accepted: c.Accepted, Sent: c.Sent - before.Sent, Failed: c.Failed - before.Failed}
+	}
+	return ExporterCounts{
+		Logs:    sub(c.Logs, before.Logs),
+		Traces:  sub(c.Traces, before.Traces),
+		Metrics: sub(c.Metrics, before.Metrics),
+		Queued:  c.Queued,
+	}
+}
+
+// signal returns the counts of the signal the items of a metric named with suffix are, or nil if none.
+func (c *ExporterCounts) signal(suffix string) *SignalCounts {
+	switch suffix {
+	case "log_records":
+		return &c.Logs
+	case "spans":
+		return &c.Traces
+	case "metric_points":
+		return &c.Metrics
+	default:
+		return nil
+	}
+}
+
+// ExporterStats reads the metrics the exporters record about themselves, to report how much data was flushed
+// or dropped when the input stops.
+type ExporterStats struct {
+	reader *sdkmetric.ManualReader
+}
+
+// NewExporterStats returns exporter stats reading the metrics recorded through a meter provider fed with Reader.
+func NewExporterStats() *ExporterStats {
+	return &ExporterStats{reader: sdkmetric.NewManualReader()}
+}
+
+// Reader returns the reader to register with the meter provider of the exporters.
+func (s *ExporterStats) Reader() sdkmetric.Reader {
+	return s.reader
+}
+
+// Collect returns the current counts of the exporters.
+func (s *ExporterStats) Collect(ctx context.Context) (ExporterCounts, error) {
+	var counts ExporterCounts
+	var rm metricdata.ResourceMetrics
+	if err := s.reader.Collect(ctx, &rm); err != nil {
+		return counts, err
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			var total *int64
+			switch {
+			case m.Name == queueSizeMetric:
+				total = &counts.Queued
+			case strings.HasPrefix(m.Name, acceptedPrefix):
+				if signal := counts.signal(strings.TrimPrefix(m.Name, acceptedPrefix)); signal != nil {
+					total = &signal.Accepted
+				}
+			case strings.HasPrefix(m.Name, sentPrefix):
+				if signal := counts.signal(strings.TrimPrefix(m.Name, sentPrefix)); signal != nil {
+					total = &signal.Sent
+				}
+			case strings.HasPrefix(m.Name, sendFailedPrefix):
+				if signal := counts.signal(strings.TrimPrefix(m.Name, sendFailedPrefix)); signal != nil {
+					total = &signal.Failed
+				}
+			}
+			if total == nil {
+				continue
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, p := range data.DataPoints {
+					*total += p.Value
+				}
+			case metricdata.Gauge[int64]:
+				for _, p := range data.DataPoints {
+					*total += p.Value
+				}
+			}
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,63 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestExporterStats(t *testing.T) {
+	stats := NewExporterStats()
+	provider := NewMeterProvider(stats.Reader())
+	meter := provider.Meter("go.opentelemetry.io/collector/exporter/exporterhelper")
+	ctx := context.Background()
+
+	acceptedLogs, err := meter.Int64Counter("otelcol_receiver_accepted_log_records")
+	require.NoError(t, err)
+	acceptedSpans, err := meter.Int64Counter("otelcol_receiver_accepted_spans")
+	require.NoError(t, err)
+	sentLogs, err := meter.Int64Counter("otelcol_exporter_sent_log_records")
+	require.NoError(t, err)
+	sentSpans, err := meter.Int64Counter("otelcol_exporter_sent_spans")
+	require.NoError(t, err)
+	failed, err := meter.Int64Counter("otelcol_exporter_send_failed_metric_points")
+	require.NoError(t, err)
+	queued := int64(4)
+	_, err = meter.Int64ObservableGauge("otelcol_exporter_queue_size", metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+		o.Observe(queued)
+		return nil
+	}))
+	require.NoError(t, err)
+
+	acceptedLogs.Add(ctx, 8)
+	acceptedSpans.Add(ctx, 5)
+	sentLogs.Add(ctx, 3)
+	before, err := stats.Collect(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ExporterCounts{Logs: SignalCounts{Accepted: 8, Sent: 3}, Traces: SignalCounts{Accepted: 5}, Queued: 4}, before)
+
+	sentLogs.Add(ctx, 2)
+	sentSpans.Add(ctx, 5)
+	failed.Add(ctx, 1)
+	queued = 1
+	after, err := stats.Collect(ctx)
+	require.NoError(t, err)
+	drained := after.Sub(before)
+	require.Equal(t, ExporterCounts{
+		Logs:    SignalCounts{Accepted: 8, Sent: 2},
+		Traces:  SignalCounts{Accepted: 5, Sent: 5},
+		Metrics: SignalCounts{Failed: 1},
+		Queued:  1,
+	}, drained)
+	require.Equal(t, int64(7), drained.Sent())
+	require.Equal(t, int64(1), drained.Failed())
+	// The log records accepted but not sent yet are left in the queue.
+	require.Equal(t, int64(3), after.Logs.Discarded())
+	require.Zero(t, after.Traces.Discarded())
+	require.Zero(t, after.Metrics.Discarded())
+}
@@ -0,0 +1,95 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package hecexporter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/splunk/otlp2splunk/internal/exporter/splunkevent"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/config/configoptional"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// collectorPath is the path of the HEC endpoint accepting events and metrics.
+const collectorPath = "/services/collector"
+
+type Config struct {
+	// ClientConfig configures the connection to HEC. Endpoint is the URL of the HEC collector endpoint,
+	// for example https://splunk:8088/services/collector.
+	confighttp.ClientConfig `mapstructure:",squash"`
+	QueueBatchConfig        configoptional.Optional[exporterhelper.QueueBatchConfig] `mapstructure:"sending_queue"`
+	BackOffConfig           configretry.BackOffConfig                                `mapstructure:"retry_on_failure"`
+	// Config holds the defaults of events and the indexes they may be routed to.
+	splunkevent.Config `mapstructure:",squash"`
+	// Token is the HEC token authenticating the requests.
+	Token configopaque.String `mapstructure:"token"`
+	// MaxContentLength is the maximum size of the uncompressed body of a request. Larger batches are split.
+	MaxContentLength int `mapstructure:"max_content_length"`
+	// UseAck waits for HEC to acknowledge each request was indexed before reporting it sent.
+	// It requires indexer acknowledgment to be enabled on the token.
+	UseAck bool `mapstructure:"use_ack"`
+	// AckPollInterval is the period between two queries of the acknowledgment status.
+	AckPollInterval time.Duration `mapstructure:"ack_poll_interval"`
+	// AckTimeout is how long to wait for an acknowledgment before sending the request again.
+	AckTimeout time.Duration `mapstructure:"ack_timeout"`
+}
+
+func (c *Config) Validate() error {
+	if err := c.Config.Validate(); err != nil {
+		return err
+	}
+	if _, err := c.collectorURL(); err != nil {
+		return err
+	}
+	if c.Token == "" {
+		return errors.New("token must be set")
+	}
+	if c.MaxContentLength <= 0 {
+		return fmt.Errorf("max_content_length must be positive, it is set to %d", c.MaxContentLength)
+	}
+	if c.UseAck && (c.AckPollInterval <= 0 || c.AckTimeout <= 0) {
+		return errors.New("ack_poll_interval and ack_timeout must be positive when use_ack is enabled")
+	}
+	return nil
+}
+
+// collectorURL returns the URL events are posted to. An endpoint without a path posts to /services/collector.
+func (c *Config) collectorURL() (*url.URL, error) {
+	if c.Endpoint == "" {
+		return nil, errors.New("endpoint must be set")
+	}
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("endpoint %q is not a valid URL: %w", c.Endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("endpoint %q must be an http or https URL", c.Endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = collectorPath
+	}
+	return u, nil
+}
+
+// ackURL returns the URL of the acknowledgment endpoint matching the collector URL.
+func (c *Config) ackURL() (*url.URL, error) {
+	u, err := c.collectorURL()
+	if err != nil {
+		return nil, err
+	}
+	i := strings.Index(u.Path, collectorPath)
+	if i < 0 {
+		return nil, fmt.Errorf("endpoint %q must point to %s to use acknowledgments", c.Endpoint, collectorPath)
+	}
+	u.Path = u.Path[:i+len(collectorPath)] + "/ack"
+	u.RawQuery = ""
+	return u, nil
+}
@@ -0,0 +1,346 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package hecexporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/splunk"
+	"github.com/splunk/otlp2splunk/internal/exporter/splunkevent"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// errNotAcknowledged is returned when HEC did not acknowledge a request within the ack timeout.
+var errNotAcknowledged = errors.New("HEC did not acknowledge the request in time")
+
+func newHECExporter(set exporter.Settings, cfg *Config) (*hecExporter, error) {
+	events, err := splunkevent.NewTranslator(set.TelemetrySettings, cfg.Config)
+	if err != nil {
+		return nil, err
+	}
+	collectorURL, err := cfg.collectorURL()
+	if err != nil {
+		return nil, err
+	}
+	e := &hecExporter{
+		TelemetrySettings: set.TelemetrySettings,
+		config:            cfg,
+		events:            events,
+		collectorURL:      collectorURL.String(),
+		channel:           uuid.NewString(),
+	}
+	if cfg.UseAck {
+		ackURL, err := cfg.ackURL()
+		if err != nil {
+			return nil, err
+		}
+		e.ackURL = ackURL.String()
+	}
+	return e, nil
+}
+
+func newLogsExporter(ctx context.Context, set exporter.Settings, cfg component.Config) (exporter.Logs, error) {
+	oCfg := cfg.(*Config)
+
+	e, err := newHECExporter(set, oCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	exp, err := exporterhelper.NewLogs(ctx, set, cfg, e.ConsumeLogs,
+		exporterhelper.WithCapabilities(consumer.Capabilities{
+			MutatesData: false,
+		}),
+		exporterhelper.WithStart(e.start),
+		exporterhelper.WithRetry(oCfg.BackOffConfig),
+		exporterhelper.WithQueue(oCfg.QueueBatchConfig))
+	if err != nil {
+		return nil, err
+	}
+	return e.events.WrapLogs(exp), nil
+}
+
+func newTracesExporter(ctx context.Context, set exporter.Settings, cfg component.Config) (exporter.Traces, error) {
+	oCfg := cfg.(*Config)
+
+	e, err := newHECExporter(set, oCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	exp, err := exporterhelper.NewTraces(ctx, set, cfg, e.ConsumeTraces,
+		exporterhelper.WithCapabilities(consumer.Capabilities{
+			MutatesData: false,
+		}),
+		exporterhelper.WithStart(e.start),
+		exporterhelper.WithRetry(oCfg.BackOffConfig),
+		exporterhelper.WithQueue(oCfg.QueueBatchConfig))
+	if err != nil {
+		return nil, err
+	}
+	return e.events.WrapTraces(exp), nil
+}
+
+func newMetricsExporter(ctx context.Context, set exporter.Settings, cfg component.Config) (exporter.Metrics, error) {
+	oCfg := cfg.(*Config)
+
+	e, err := newHECExporter(set, oCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	exp, err := exporterhelper.NewMetrics(ctx, set, cfg, e.ConsumeMetrics,
+		exporterhelper.WithCapabilities(consumer.Capabilities{
+			MutatesData: false,
+		}),
+		exporterhelper.WithStart(e.start),
+		exporterhelper.WithRetry(oCfg.BackOffConfig),
+		exporterhelper.WithQueue(oCfg.QueueBatchConfig))
+	if err != nil {
+		return nil, err
+	}
+	return e.events.WrapMetrics(exp), nil
+}
+
+// hecExporter posts events to the HEC collector endpoint, in batches of at most MaxContentLength bytes.
+type hecExporter struct {
+	TelemetrySettings component.TelemetrySettings
+	config            *Config
+	events            *splunkevent.Translator
+	client            *http.Client
+	collectorURL      string
+	ackURL            string
+	// channel identifies the exporter to HEC, which tracks acknowledgments per channel.
+	channel string
+}
+
+// hecResponse is the body of the responses of the collector and ack endpoints.
+type hecResponse struct {
+	Text  string          `json:"text"`
+	Code  int             `json:"code"`
+	AckID *uint64         `json:"ackId"`
+	Acks  map[string]bool `json:"acks"`
+}
+
+func (he *hecExporter) start(ctx context.Context, host component.Host) error {
+	client, err := he.config.ClientConfig.ToClient(ctx, host.GetExtensions(), he.TelemetrySettings)
+	if err != nil {
+		return err
+	}
+	he.client = client
+	return nil
+}
+
+// ConsumeLogs sends the events of ld. When a request fails after others were sent, the error holds the log records
+// left to send, so a retry does not send the same events twice.
+func (he *hecExporter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	batch := he.events.Logs(ctx, ld)
+	sent, err := he.send(ctx, batch.Events)
+	if err == nil || sent == 0 || consumererror.IsPermanent(err) {
+		return err
+	}
+	return consumererror.NewLogs(err, unsentLogs(ld, batch.Items[sent]))
+}
+
+// ConsumeTraces sends the events of td. When a request fails after others were sent, the error holds the spans
+// left to send.
+func (he *hecExporter) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	batch := he.events.Traces(ctx, td)
+	sent, err := he.send(ctx, batch.Events)
+	if err == nil || sent == 0 || consumererror.IsPermanent(err) {
+		return err
+	}
+	return consumererror.NewTraces(err, unsentTraces(td, batch.Items[sent]))
+}
+
+// ConsumeMetrics sends the events of md. When a request fails after others were sent, the error holds the metrics
+// left to send. A metric whose events were partly sent is sent again whole.
+func (he *hecExporter) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	batch := he.events.Metrics(ctx, md)
+	sent, err := he.send(ctx, batch.Events)
+	if err == nil || sent == 0 || consumererror.IsPermanent(err) {
+		return err
+	}
+	return consumererror.NewMetrics(err, unsentMetrics(md, batch.Items[sent]))
+}
+
+// send posts the events, splitting them in requests of at most MaxContentLength bytes, and returns how many events
+// were sent before a request failed. An event larger than MaxContentLength is sent in a request of its own.
+func (he *hecExporter) send(ctx context.Context, events []*translator.Event) (int, error) {
+	var body bytes.Buffer
+	sent, pending := 0, 0
+	for _, event := range events {
+		b, err := json.Marshal(event)
+		if err != nil {
+			return sent, consumererror.NewPermanent(err)
+		}
+		if body.Len() > 0 && body.Len()+len(b)+1 > he.config.MaxContentLength {
+			if err = he.post(ctx, body.Bytes()); err != nil {
+				return sent, err
+			}
+			sent += pending
+			pending = 0
+			body.Reset()
+		}
+		body.Write(b)
+		body.WriteByte('\n')
+		pending++
+	}
+	if body.Len() == 0 {
+		return sent, nil
+	}
+	if err := he.post(ctx, body.Bytes()); err != nil {
+		return sent, err
+	}
+	return sent + pending, nil
+}
+
+// unsentLogs returns a copy of ld without the log records before position first.
+func unsentLogs(ld plog.Logs, first int) plog.Logs {
+	unsent := plog.NewLogs()
+	ld.CopyTo(unsent)
+	item := 0
+	unsent.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+			sl.LogRecords().RemoveIf(func(plog.LogRecord) bool {
+				item++
+				return item <= first
+			})
+			return sl.LogRecords().Len() == 0
+		})
+		return rl.ScopeLogs().Len() == 0
+	})
+	return unsent
+}
+
+// unsentTraces returns a copy of td without the spans before position first.
+func unsentTraces(td ptrace.Traces, first int) ptrace.Traces {
+	unsent := ptrace.NewTraces()
+	td.CopyTo(unsent)
+	item := 0
+	unsent.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		rs.ScopeSpans().RemoveIf(func(ss ptrace.ScopeSpans) bool {
+			ss.Spans().RemoveIf(func(ptrace.Span) bool {
+				item++
+				return item <= first
+			})
+			return ss.Spans().Len() == 0
+		})
+		return rs.ScopeSpans().Len() == 0
+	})
+	return unsent
+}
+
+// unsentMetrics returns a copy of md without the metrics before position first.
+func unsentMetrics(md pmetric.Metrics, first int) pmetric.Metrics {
+	unsent := pmetric.NewMetrics()
+	md.CopyTo(unsent)
+	item := 0
+	unsent.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(func(pmetric.Metric) bool {
+				item++
+				return item <= first
+			})
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+	return unsent
+}
+
+// post sends a batch of events and waits for its acknowledgment when UseAck is set.
+func (he *hecExporter) post(ctx context.Context, body []byte) error {
+	response, err := he.do(ctx, he.collectorURL, body)
+	if err != nil {
+		return err
+	}
+	if !he.config.UseAck {
+		return nil
+	}
+	if response.AckID == nil {
+		return consumererror.NewPermanent(errors.New("HEC did not return an ackId, indexer acknowledgment must be enabled on the token"))
+	}
+	return he.waitForAck(ctx, *response.AckID)
+}
+
+// waitForAck polls the ack endpoint until HEC reports the request identified by ackID was indexed.
+func (he *hecExporter) waitForAck(ctx context.Context, ackID uint64) error {
+	query, err := json.Marshal(map[string][]uint64{"acks": {ackID}})
+	if err != nil {
+		return err
+	}
+	timeout := time.NewTimer(he.config.AckTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(he.config.AckPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout.C:
+			return errNotAcknowledged
+		case <-ticker.C:
+			response, err := he.do(ctx, he.ackURL, query)
+			if err != nil {
+				he.TelemetrySettings.Logger.Debug("Cannot query the acknowledgment status", zap.Error(err))
+				continue
+			}
+			if response.Acks[strconv.FormatUint(ackID, 10)] {
+				return nil
+			}
+		}
+	}
+}
+
+// do posts body to url and decodes the response. Errors HEC may recover from are retryable, others permanent.
+func (he *hecExporter) do(ctx context.Context, url string, body []byte) (hecResponse, error) {
+	var response hecResponse
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return response, consumererror.NewPermanent(err)
+	}
+	req.Header.Set("Authorization", "Splunk "+string(he.config.Token))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Splunk-Request-Channel", he.channel)
+
+	resp, err := he.client.Do(req)
+	if err != nil {
+		return response, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return response, err
+	}
+	_ = json.Unmarshal(b, &response)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return response, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return response, fmt.Errorf("HEC responded %s: %s", resp.Status, response.Text)
+	default:
+		return response, consumererror.NewPermanent(fmt.Errorf("HEC responded %s: %s", resp.Status, response.Text))
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package hecexporter
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configoptional"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// fakeHEC records the events posted to its collector endpoint and acknowledges them on its ack endpoint.
+type fakeHEC struct {
+	t      *testing.T
+	token  string
+	status int
+	// accepted is the number of requests accepted before the collector endpoint answers with status.
+	accepted int
+
+	mu       sync.Mutex
+	requests [][]map[string]any
+	channels []string
+	acked    bool
+}
+
+func (f *fakeHEC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Splunk "+f.token {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"text":"Invalid token","code":4}`))
+		return
+	}
+	assert.Equal(f.t, "gzip", r.Header.Get("Content-Encoding"))
+	body, err := gzip.NewReader(r.Body)
+	require.NoError(f.t, err)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.URL.Path {
+	case "/services/collector":
+		var events []map[string]any
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			var event map[string]any
+			require.NoError(f.t, json.Unmarshal(scanner.Bytes(), &event))
+			events = append(events, event)
+		}
+		f.requests = append(f.requests, events)
+		f.channels = append(f.channels, r.Header.Get("X-Splunk-Request-Channel"))
+		if f.status != 0 && len(f.requests) > f.accepted {
+			w.WriteHeader(f.status)
+			_, _ = w.Write([]byte(`{"text":"Server is busy","code":9}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"text":"Success","code":0,"ackId":42}`))
+	case "/services/collector/ack":
+		query, err := io.ReadAll(body)
+		require.NoError(f.t, err)
+		assert.JSONEq(f.t, `{"acks":[42]}`, string(query))
+		// The first query reports the request is not indexed yet.
+		_, _ = w.Write([]byte(`{"acks":{"42":` + strconv.FormatBool(f.acked) + `}}`))
+		f.acked = true
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func prepareLogs(bodies ...string) plog.Logs {
+	logs := plog.NewLogs()
+	sl := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	for _, body := range bodies {
+		logRecord := sl.LogRecords().AppendEmpty()
+		logRecord.Body().SetStr(body)
+		logRecord.Attributes().PutStr("host.name", "myhost")
+	}
+	return logs
+}
+
+func newTestExporter(t *testing.T, hec *fakeHEC, configure func(*Config)) *hecExporter {
+	server := httptest.NewServer(hec)
+	t.Cleanup(server.Close)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	cfg.Token = "00000000-0000-0000-0000-000000000000"
+	cfg.Index = "main"
+	cfg.AckPollInterval = 10 * time.Millisecond
+	hec.token = string(cfg.Token)
+	if configure != nil {
+		configure(cfg)
+	}
+	require.NoError(t, cfg.Validate())
+
+	e, err := newHECExporter(exportertest.NewNopSettings(NewFactory().Type()), cfg)
+	require.NoError(t, err)
+	require.NoError(t, e.start(context.Background(), componenttest.NewNopHost()))
+	return e
+}
+
+func TestConsumeLogs(t *testing.T) {
+	hec := &fakeHEC{t: t}
+	e := newTestExporter(t, hec, nil)
+
+	require.NoError(t, e.ConsumeLogs(context.Background(), prepareLogs("first", "second")))
+
+	require.Len(t, hec.requests, 1)
+	require.Len(t, hec.requests[0], 2)
+	assert.Equal(t, "first", hec.requests[0][0]["event"])
+	assert.Equal(t, "main", hec.requests[0][0]["index"])
+	assert.Equal(t, "myhost", hec.requests[0][0]["host"])
+	assert.Equal(t, "second", hec.requests[0][1]["event"])
+}
+
+func TestConsumeLogsSplitsLargeBatches(t *testing.T) {
+	hec := &fakeHEC{t: t}
+	e := newTestExporter(t, hec, func(cfg *Config) {
+		cfg.MaxContentLength = 150
+	})
+
+	require.NoError(t, e.ConsumeLogs(context.Background(), prepareLogs("first", "second", strings.Repeat("large", 50))))
+
+	require.Len(t, hec.requests, 2)
+	require.Len(t, hec.requests[0], 2)
+	require.Len(t, hec.requests[1], 1, "an event larger than max_content_length is sent on its own")
+	assert.Equal(t, strings.Repeat("large", 50), hec.requests[1][0]["event"])
+}
+
+func TestConsumeLogsRetriesUnsentEvents(t *testing.T) {
+	hec := &fakeHEC{t: t, status: http.StatusServiceUnavailable, accepted: 1}
+	e := newTestExporter(t, hec, func(cfg *Config) {
+		cfg.MaxContentLength = 150
+	})
+
+	ld := prepareLogs("first", "second", strings.Repeat("large", 50))
+	err := e.ConsumeLogs(context.Background(), ld)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	require.Len(t, hec.requests, 2)
+
+	// Only the log record of the failed request is left to retry.
+	var unsent consumererror.Logs
+	require.ErrorAs(t, err, &unsent)
+	records := unsent.Data().ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 1, unsent.Data().LogRecordCount())
+	assert.Equal(t, strings.Repeat("large", 50), records.At(0).Body().Str())
+	assert.Equal(t, 3, ld.LogRecordCount(), "the logs being exported are not modified")
+
+	hec.status = 0
+	require.NoError(t, e.ConsumeLogs(context.Background(), unsent.Data()))
+	require.Len(t, hec.requests, 3)
+	require.Len(t, hec.requests[2], 1)
+}
+
+func TestConsumeLogsWaitsForAck(t *testing.T) {
+	hec := &fakeHEC{t: t}
+	e := newTestExporter(t, hec, func(cfg *Config) {
+		cfg.UseAck = true
+	})
+
+	require.NoError(t, e.ConsumeLogs(context.Background(), prepareLogs("acked")))
+	assert.True(t, hec.acked)
+	assert.Equal(t, []string{e.channel}, hec.channels)
+	assert.NotEmpty(t, e.channel)
+}
+
+func TestConsumeLogsAckTimeout(t *testing.T) {
+	hec := &fakeHEC{t: t}
+	e := newTestExporter(t, hec, func(cfg *Config) {
+		cfg.UseAck = true
+		cfg.AckPollInterval = time.Hour
+		cfg.AckTimeout = 10 * time.Millisecond
+	})
+
+	err := e.ConsumeLogs(context.Background(), prepareLogs("lost"))
+	require.ErrorIs(t, err, errNotAcknowledged)
+	assert.False(t, consumererror.IsPermanent(err), "requests not acknowledged must be retried")
+}
+
+func TestConsumeLogsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		hecToken  string
+		permanent bool
+	}{
+		{name: "server busy", status: http.StatusServiceUnavailable},
+		{name: "too many requests", status: http.StatusTooManyRequests},
+		{name: "bad request", status: http.StatusBadRequest, permanent: true},
+		{name: "invalid token", hecToken: "another token", permanent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hec := &fakeHEC{t: t, status: tt.status}
+			e := newTestExporter(t, hec, nil)
+			if tt.hecToken != "" {
+				hec.token = tt.hecToken
+			}
+
+			err := e.ConsumeLogs(context.Background(), prepareLogs("refused"))
+			require.Error(t, err)
+			assert.Equal(t, tt.permanent, consumererror.IsPermanent(err))
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*Config)
+		errMsg    string
+	}{
+		{name: "valid", configure: func(*Config) {}},
+		{name: "missing endpoint", configure: func(cfg *Config) { cfg.Endpoint = "" }, errMsg: "endpoint must be set"},
+		{name: "invalid scheme", configure: func(cfg *Config) { cfg.Endpoint = "tcp://splunk:8088" }, errMsg: "must be an http or https URL"},
+		{name: "missing token", configure: func(cfg *Config) { cfg.Token = "" }, errMsg: "token must be set"},
+		{name: "max content length", configure: func(cfg *Config) { cfg.MaxContentLength = 0 }, errMsg: "max_content_length must be positive"},
+		{name: "ack timeout", configure: func(cfg *Config) {
+			cfg.UseAck = true
+			cfg.AckTimeout = 0
+		}, errMsg: "ack_poll_interval and ack_timeout must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = "https://splunk:8088"
+			cfg.Token = "token"
+			tt.configure(cfg)
+			err := cfg.Validate()
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestConfigURLs(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "https://splunk:8088"
+	collectorURL, err := cfg.collectorURL()
+	require.NoError(t, err)
+	assert.Equal(t, "https://splunk:8088/services/collector", collectorURL.String())
+	ackURL, err := cfg.ackURL()
+	require.NoError(t, err)
+	assert.Equal(t, "https://splunk:8088/services/collector/ack", ackURL.String())
+
+	cfg.Endpoint = "https://splunk:8088/services/collector/event?channel=abc"
+	ackURL, err = cfg.ackURL()
+	require.NoError(t, err)
+	assert.Equal(t, "https://splunk:8088/services/collector/ack", ackURL.String())
+}
+
+func TestCreateExporters(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "https://splunk:8088"
+	cfg.Token = "token"
+	cfg.QueueBatchConfig = configoptional.None[exporterhelper.QueueBatchConfig]()
+	set := exportertest.NewNopSettings(factory.Type())
+
+	logs, err := factory.CreateLogs(context.Background(), set, cfg)
+	require.NoError(t, err)
+	require.NoError(t, logs.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, logs.Shutdown(context.Background()))
+
+	metrics, err := factory.CreateMetrics(context.Background(), set, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, metrics)
+
+	traces, err := factory.CreateTraces(context.Background(), set, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, traces)
+}
@@ -0,0 +1,64 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package hecexporter
+
+import (
+	"time"
+
+	"github.com/splunk/otlp2splunk/internal/exporter/splunkevent"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configoptional"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// This file implements factory for HEC exporter.
+
+const (
+	typeStr        = "hec"
+	stabilityLevel = component.StabilityLevelDevelopment
+
+	// defaultMaxContentLength stays below the default max_content_length of splunkd.
+	defaultMaxContentLength = 2 * 1024 * 1024
+)
+
+// NewFactory creates a factory for HEC exporter.
+func NewFactory() exporter.Factory {
+	return exporter.NewFactory(
+		component.MustNewType(typeStr),
+		createDefaultConfig,
+		exporter.WithLogs(newLogsExporter, stabilityLevel),
+		exporter.WithMetrics(newMetricsExporter, stabilityLevel),
+		exporter.WithTraces(newTracesExporter, stabilityLevel),
+	)
+}
+
+// CreateDefaultConfig creates the default configuration for HEC exporter.
+func createDefaultConfig() component.Config {
+	clientConfig := confighttp.NewDefaultClientConfig()
+	clientConfig.Timeout = 10 * time.Second
+	clientConfig.Compression = configcompression.TypeGzip
+
+	queueConfig := exporterhelper.NewDefaultQueueConfig()
+	queueConfig.Batch = configoptional.Some(exporterhelper.BatchConfig{
+		FlushTimeout: 200 * time.Millisecond,
+		Sizer:        exporterhelper.RequestSizerTypeItems,
+		MinSize:      8192,
+	})
+
+	return &Config{
+		ClientConfig:     clientConfig,
+		QueueBatchConfig: configoptional.Some(queueConfig),
+		BackOffConfig:    configretry.NewDefaultBackOffConfig(),
+		Config: splunkevent.Config{
+			IndexViolationAction: splunkevent.IndexViolationDrop,
+		},
+		MaxContentLength: defaultMaxContentLength,
+		AckPollInterval:  time.Second,
+		AckTimeout:       time.Minute,
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+// Package splunkevent translates OTLP data into Splunk HEC events, shared by the exporters writing them to Splunk.
+package splunkevent
+
+import "fmt"
+
+const (
+	// IndexViolationDrop drops events routed to an index that is not allowed.
+	IndexViolationDrop = "drop"
+	// IndexViolationDefault routes events sent to an index that is not allowed to the default index.
+	IndexViolationDefault = "default"
+	// IndexViolationReject rejects OTLP requests containing events routed to an index that is not allowed.
+	IndexViolationReject = "reject"
+)
+
+// Config holds the defaults of events and the indexes they may be routed to.
+type Config struct {
+	// Index is the default index of events. The com.splunk.index attribute overrides it.
+	Index string `mapstructure:"index"`
+	// SourceType is the default sourcetype of events. The com.splunk.sourcetype attribute overrides it.
+	SourceType string `mapstructure:"sourcetype"`
+	// Source is the default source of events. The com.splunk.source attribute overrides it.
+	Source string `mapstructure:"source"`
+	// Host is the default host of events. The host.name attribute overrides it.
+	Host string `mapstructure:"host"`
+	// AllowedIndexes restricts the indexes events may be routed to. Each entry is an index name or a regular
+	// expression matching whole index names. Events may be routed to any index when empty.
+	AllowedIndexes []string `mapstructure:"allowed_indexes"`
+	// IndexViolationAction is applied to events routed to an index that is not allowed: drop, default or reject.
+	IndexViolationAction string `mapstructure:"index_violation_action"`
+}
+
+func (c *Config) Validate() error {
+	switch c.IndexViolationAction {
+	case IndexViolationDrop, IndexViolationDefault, IndexViolationReject:
+	default:
+		return fmt.Errorf("index_violation_action %q is not supported, it must be one of drop, default or reject", c.IndexViolationAction)
+	}
+	for _, expr := range c.AllowedIndexes {
+		if _, err := compileIndexPattern(expr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
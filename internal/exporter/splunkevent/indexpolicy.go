@@ -0,0 +1,160 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkevent
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/splunk"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const scopeName = "github.com/splunk/otlp2splunk/internal/exporter/splunkevent"
+
+// indexPolicy enforces the allow-list of indexes events may be routed to.
+type indexPolicy struct {
+	violations   metric.Int64Counter
+	action       string
+	defaultIndex string
+	allowed      []*regexp.Regexp
+}
+
+func newIndexPolicy(set component.TelemetrySettings, cfg Config) (*indexPolicy, error) {
+	p := &indexPolicy{action: cfg.IndexViolationAction, defaultIndex: cfg.Index}
+	for _, expr := range cfg.AllowedIndexes {
+		re, err := compileIndexPattern(expr)
+		if err != nil {
+			return nil, err
+		}
+		p.allowed = append(p.allowed, re)
+	}
+	var err error
+	p.violations, err = set.MeterProvider.Meter(scopeName).Int64Counter(
+		"otlp_input_index_violations",
+		metric.WithDescription("Number of events routed to an index that is not allowed"),
+		metric.WithUnit("{events}"),
+	)
+	return p, err
+}
+
+// compileIndexPattern matches a whole index name, so plain index names match only themselves.
+func compileIndexPattern(expr string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowed index %q: %w", expr, err)
+	}
+	return re, nil
+}
+
+func (p *indexPolicy) enabled() bool {
+	return len(p.allowed) > 0
+}
+
+// allows returns true if events may be sent to index. The default index, chosen by the administrator of the input,
+// is always allowed.
+func (p *indexPolicy) allows(index string) bool {
+	if !p.enabled() || index == p.defaultIndex {
+		return true
+	}
+	for _, re := range p.allowed {
+		if re.MatchString(index) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply enforces the policy on event, and returns false if the event must be dropped.
+func (p *indexPolicy) apply(ctx context.Context, event *translator.Event) bool {
+	if p.allows(event.Index) {
+		return true
+	}
+	p.violations.Add(ctx, 1, metric.WithAttributes(attribute.String("action", p.action)))
+	if p.action == IndexViolationDefault {
+		event.Index = p.defaultIndex
+		return true
+	}
+	return false
+}
+
+func (p *indexPolicy) reject(ctx context.Context, index string, count int) error {
+	p.violations.Add(ctx, int64(count), metric.WithAttributes(attribute.String("action", p.action)))
+	return consumererror.NewPermanent(fmt.Errorf("index %q is not allowed", index))
+}
+
+// resourceIndex returns the index set on res, or the default index.
+func (p *indexPolicy) resourceIndex(res pcommon.Resource, key string) string {
+	if v, ok := res.Attributes().Get(key); ok {
+		return v.Str()
+	}
+	return p.defaultIndex
+}
+
+// rejectingLogs rejects logs routed to an index that is not allowed before they are queued,
+// so the OTLP client receives the error.
+type rejectingLogs struct {
+	exporter.Logs
+	policy *indexPolicy
+}
+
+func (r *rejectingLogs) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	key := translator.DefaultHecToOtelAttrs().Index
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		index := r.policy.resourceIndex(rl.Resource(), key)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				recordIndex := index
+				if v, ok := records.At(k).Attributes().Get(key); ok {
+					recordIndex = v.Str()
+				}
+				if !r.policy.allows(recordIndex) {
+					return r.policy.reject(ctx, recordIndex, ld.LogRecordCount())
+				}
+			}
+		}
+	}
+	return r.Logs.ConsumeLogs(ctx, ld)
+}
+
+type rejectingTraces struct {
+	exporter.Traces
+	policy *indexPolicy
+}
+
+func (r *rejectingTraces) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	key := translator.DefaultHecToOtelAttrs().Index
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		if index := r.policy.resourceIndex(td.ResourceSpans().At(i).Resource(), key); !r.policy.allows(index) {
+			return r.policy.reject(ctx, index, td.SpanCount())
+		}
+	}
+	return r.Traces.ConsumeTraces(ctx, td)
+}
+
+type rejectingMetrics struct {
+	exporter.Metrics
+	policy *indexPolicy
+}
+
+func (r *rejectingMetrics) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	key := translator.DefaultHecToOtelAttrs().Index
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		if index := r.policy.resourceIndex(md.ResourceMetrics().At(i).Resource(), key); !r.policy.allows(index) {
+			return r.policy.reject(ctx, index, md.DataPointCount())
+		}
+	}
+	return r.Metrics.ConsumeMetrics(ctx, md)
+}
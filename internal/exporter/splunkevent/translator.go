@@ -0,0 +1,166 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkevent
+
+import (
+	"context"
+
+	translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/splunk"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// Translator translates OTLP data into HEC events, applying the defaults and the index policy of its configuration.
+type Translator struct {
+	logger *zap.Logger
+	config Config
+	policy *indexPolicy
+}
+
+// Batch holds the events translated from OTLP data.
+type Batch struct {
+	Events []*translator.Event
+	// Items holds, for each event, the position of the log record, span or metric it was translated from among
+	// those of the data, so the data whose events were not sent can be retried alone.
+	Items []int
+}
+
+func (b *Batch) add(event *translator.Event, item int) {
+	b.Events = append(b.Events, event)
+	b.Items = append(b.Items, item)
+}
+
+// NewTranslator returns a translator recording index violations with the meter provider of set.
+func NewTranslator(set component.TelemetrySettings, cfg Config) (*Translator, error) {
+	policy, err := newIndexPolicy(set, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Translator{logger: set.Logger, config: cfg, policy: policy}, nil
+}
+
+// Logs returns the events of the log records of ld that may be sent to their index.
+func (t *Translator) Logs(ctx context.Context, ld plog.Logs) Batch {
+	toOtelAttrs := translator.DefaultHecToOtelAttrs()
+	toHecAttrs := translator.DefaultOtelToHecFields()
+
+	var batch Batch
+	item := 0
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		r := t.withDefaultHost(rl.Resource(), toOtelAttrs)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				logRecord := sl.LogRecords().At(k)
+				event := translator.LogToSplunkEvent(r, logRecord, toOtelAttrs, toHecAttrs, t.config.Source, t.config.SourceType, t.config.Index)
+				if event != nil && t.policy.apply(ctx, event) {
+					batch.add(event, item)
+				}
+				item++
+			}
+		}
+	}
+	return batch
+}
+
+// Traces returns the events of the spans of td that may be sent to their index.
+func (t *Translator) Traces(ctx context.Context, td ptrace.Traces) Batch {
+	toOtelAttrs := translator.DefaultHecToOtelAttrs()
+
+	var batch Batch
+	item := 0
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		r := t.withDefaultHost(rs.Resource(), toOtelAttrs)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				event := translator.SpanToSplunkEvent(r, span, toOtelAttrs, t.config.Source, t.config.SourceType, t.config.Index)
+				if t.policy.apply(ctx, event) {
+					batch.add(event, item)
+				}
+				item++
+			}
+		}
+	}
+	return batch
+}
+
+// Metrics returns the events of the data points of md that may be sent to their index. The events of a metric
+// share its position.
+func (t *Translator) Metrics(ctx context.Context, md pmetric.Metrics) Batch {
+	toOtelAttrs := translator.DefaultHecToOtelAttrs()
+
+	var batch Batch
+	item := 0
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		r := t.withDefaultHost(rm.Resource(), toOtelAttrs)
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				m := sm.Metrics().At(k)
+				for _, result := range translator.MetricToSplunkEvent(r, m, t.logger, toOtelAttrs, t.config.Source, t.config.SourceType, t.config.Index) {
+					if t.policy.apply(ctx, result) {
+						batch.add(result, item)
+					}
+				}
+				item++
+			}
+		}
+	}
+	return batch
+}
+
+// withDefaultHost returns r, or a copy of r with the configured default host when r lacks the host attribute.
+// The attributes of a log record still take precedence over the default host.
+func (t *Translator) withDefaultHost(r pcommon.Resource, toOtelAttrs translator.HecToOtelAttrs) pcommon.Resource {
+	if t.config.Host == "" {
+		return r
+	}
+	if _, ok := r.Attributes().Get(toOtelAttrs.Host); ok {
+		return r
+	}
+	withHost := pcommon.NewResource()
+	r.CopyTo(withHost)
+	withHost.Attributes().PutStr(toOtelAttrs.Host, t.config.Host)
+	return withHost
+}
+
+// rejectsIndexViolations returns true if requests routed to an index that is not allowed must be rejected
+// before they are queued, so the OTLP client receives the error.
+func (t *Translator) rejectsIndexViolations() bool {
+	return t.policy.enabled() && t.config.IndexViolationAction == IndexViolationReject
+}
+
+// WrapLogs returns exp, rejecting logs routed to an index that is not allowed when the policy requires it.
+func (t *Translator) WrapLogs(exp exporter.Logs) exporter.Logs {
+	if !t.rejectsIndexViolations() {
+		return exp
+	}
+	return &rejectingLogs{Logs: exp, policy: t.policy}
+}
+
+// WrapTraces returns exp, rejecting traces routed to an index that is not allowed when the policy requires it.
+func (t *Translator) WrapTraces(exp exporter.Traces) exporter.Traces {
+	if !t.rejectsIndexViolations() {
+		return exp
+	}
+	return &rejectingTraces{Traces: exp, policy: t.policy}
+}
+
+// WrapMetrics returns exp, rejecting metrics routed to an index that is not allowed when the policy requires it.
+func (t *Translator) WrapMetrics(exp exporter.Metrics) exporter.Metrics {
+	if !t.rejectsIndexViolations() {
+		return exp
+	}
+	return &rejectingMetrics{Metrics: exp, policy: t.policy}
+}
@@ -0,0 +1,66 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkevent
+
+import (
+	"testing"
+
+	translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/splunk"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestTranslatorLogs(t *testing.T) {
+	tr, err := NewTranslator(componenttest.NewNopTelemetrySettings(), Config{
+		Index:                "otlp",
+		SourceType:           "otlp:logs",
+		Host:                 "forwarder-1",
+		AllowedIndexes:       []string{"otlp_.*"},
+		IndexViolationAction: IndexViolationDefault,
+	})
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	records.AppendEmpty().Body().SetStr("defaults")
+	routed := records.AppendEmpty()
+	routed.Body().SetStr("routed")
+	routed.Attributes().PutStr("com.splunk.index", "otlp_app")
+	routed.Attributes().PutStr("host.name", "myhost")
+	forbidden := records.AppendEmpty()
+	forbidden.Body().SetStr("forbidden")
+	forbidden.Attributes().PutStr("com.splunk.index", "_audit")
+	// A host explicitly named unknown is kept.
+	unknown := records.AppendEmpty()
+	unknown.Body().SetStr("unknown")
+	unknown.Attributes().PutStr("host.name", "unknown")
+
+	batch := tr.Logs(t.Context(), ld)
+	require.Equal(t, []int{0, 1, 2, 3}, batch.Items)
+	events := batch.Events
+	require.Len(t, events, 4)
+	for i, expected := range []translator.Event{
+		{Event: "defaults", Host: "forwarder-1", SourceType: "otlp:logs", Index: "otlp"},
+		{Event: "routed", Host: "myhost", SourceType: "otlp:logs", Index: "otlp_app"},
+		{Event: "forbidden", Host: "forwarder-1", SourceType: "otlp:logs", Index: "otlp"},
+		{Event: "unknown", Host: "unknown", SourceType: "otlp:logs", Index: "otlp"},
+	} {
+		require.Equal(t, expected.Event, events[i].Event)
+		require.Equal(t, expected.Host, events[i].Host)
+		require.Equal(t, expected.SourceType, events[i].SourceType)
+		require.Equal(t, expected.Index, events[i].Index)
+	}
+	// The default host does not change the data other exporters may read.
+	require.Zero(t, ld.ResourceLogs().At(0).Resource().Attributes().Len())
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := Config{IndexViolationAction: IndexViolationDrop, AllowedIndexes: []string{"main", "otlp_("}}
+	require.ErrorContains(t, cfg.Validate(), `invalid allowed index "otlp_("`)
+
+	cfg.AllowedIndexes = nil
+	cfg.IndexViolationAction = "ignore"
+	require.EqualError(t, cfg.Validate(), `index_violation_action "ignore" is not supported, it must be one of drop, default or reject`)
+}
@@ -4,10 +4,34 @@
 package stdoutexporter
 
 import (
+	"fmt"
+	"io"
+
+	"github.com/splunk/otlp2splunk/internal/exporter/splunkevent"
 	"go.opentelemetry.io/collector/config/configoptional"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 type Config struct {
 	QueueBatchConfig configoptional.Optional[exporterhelper.QueueBatchConfig] `mapstructure:"batch_config"`
+	// Config holds the defaults of events and the indexes they may be routed to.
+	splunkevent.Config `mapstructure:",squash"`
+	// StreamingMode is the format events are written in: xml, or simple for lines of HEC JSON.
+	StreamingMode string `mapstructure:"streaming_mode"`
+	// Stanza is the name of the input stanza events are attributed to in xml streaming mode.
+	Stanza string `mapstructure:"stanza"`
+	// Output is where events are written instead of stdout, when set.
+	Output io.Writer `mapstructure:"-"`
+}
+
+func (c *Config) Validate() error {
+	if err := c.Config.Validate(); err != nil {
+		return err
+	}
+	switch c.StreamingMode {
+	case StreamingModeSimple, StreamingModeXML:
+	default:
+		return fmt.Errorf("streaming_mode %q is not supported, it must be either simple or xml", c.StreamingMode)
+	}
+	return nil
 }
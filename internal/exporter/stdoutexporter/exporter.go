@@ -8,8 +8,8 @@ import (
 	"errors"
 	"os"
 
-	"github.com/goccy/go-json"
 	translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/splunk"
+	"github.com/splunk/otlp2splunk/internal/exporter/splunkevent"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
@@ -22,130 +22,114 @@ import (
 
 var stdoutWriter = defaultStdoutWriter
 
+func newStdoutExporter(set exporter.Settings, cfg *Config) (*stdoutExporter, error) {
+	events, err := splunkevent.NewTranslator(set.TelemetrySettings, cfg.Config)
+	if err != nil {
+		return nil, err
+	}
+	return &stdoutExporter{
+		TelemetrySettings: set.TelemetrySettings,
+		config:            cfg,
+		events:            events,
+	}, nil
+}
+
 func newLogsExporter(ctx context.Context, set exporter.Settings, cfg component.Config) (exporter.Logs, error) {
 	oCfg := cfg.(*Config)
 
-	e := &stdoutExporter{}
+	e, err := newStdoutExporter(set, oCfg)
+	if err != nil {
+		return nil, err
+	}
 
-	return exporterhelper.NewLogs(ctx, set, cfg, e.ConsumeLogs,
+	exp, err := exporterhelper.NewLogs(ctx, set, cfg, e.ConsumeLogs,
 		exporterhelper.WithCapabilities(consumer.Capabilities{
 			MutatesData: false,
 		}),
 		exporterhelper.WithQueue(oCfg.QueueBatchConfig))
+	if err != nil {
+		return nil, err
+	}
+	return e.events.WrapLogs(exp), nil
 }
 
 func newTracesExporter(ctx context.Context, set exporter.Settings, cfg component.Config) (exporter.Traces, error) {
 	oCfg := cfg.(*Config)
 
-	e := &stdoutExporter{}
+	e, err := newStdoutExporter(set, oCfg)
+	if err != nil {
+		return nil, err
+	}
 
-	return exporterhelper.NewTraces(ctx, set, cfg, e.ConsumeTraces,
+	exp, err := exporterhelper.NewTraces(ctx, set, cfg, e.ConsumeTraces,
 		exporterhelper.WithCapabilities(consumer.Capabilities{
 			MutatesData: false,
 		}),
 		exporterhelper.WithQueue(oCfg.QueueBatchConfig))
+	if err != nil {
+		return nil, err
+	}
+	return e.events.WrapTraces(exp), nil
 }
 
 func newMetricsExporter(ctx context.Context, set exporter.Settings, cfg component.Config) (exporter.Metrics, error) {
 	oCfg := cfg.(*Config)
 
-	e := &stdoutExporter{}
+	e, err := newStdoutExporter(set, oCfg)
+	if err != nil {
+		return nil, err
+	}
 
-	return exporterhelper.NewMetrics(ctx, set, cfg, e.ConsumeMetrics,
+	exp, err := exporterhelper.NewMetrics(ctx, set, cfg, e.ConsumeMetrics,
 		exporterhelper.WithCapabilities(consumer.Capabilities{
 			MutatesData: false,
 		}),
 		exporterhelper.WithQueue(oCfg.QueueBatchConfig))
+	if err != nil {
+		return nil, err
+	}
+	return e.events.WrapMetrics(exp), nil
 }
 
 type stdoutExporter struct {
 	TelemetrySettings component.TelemetrySettings
+	config            *Config
+	events            *splunkevent.Translator
 }
 
-func (se *stdoutExporter) ConsumeLogs(_ context.Context, ld plog.Logs) error {
-	toOtelAttrs := translator.DefaultHecToOtelAttrs()
-	toHecAttrs := translator.DefaultOtelToHecFields()
-
-	var errs []error
-	for i := 0; i < ld.ResourceLogs().Len(); i++ {
-		rl := ld.ResourceLogs().At(i)
-		r := rl.Resource()
-		for j := 0; j < rl.ScopeLogs().Len(); j++ {
-			sl := rl.ScopeLogs().At(j)
-			for k := 0; k < sl.LogRecords().Len(); k++ {
-				logRecord := sl.LogRecords().At(k)
-				event := translator.LogToSplunkEvent(r, logRecord, toOtelAttrs, toHecAttrs, "", "", "")
-				if event == nil {
-					continue
-				}
-				b, err := json.Marshal(&event)
-				if err != nil {
-					errs = append(errs, err)
-				} else {
-					if err = se.writeToStdout(b); err != nil {
-						errs = append(errs, err)
-					}
-				}
-			}
-		}
-	}
-	return errors.Join(errs...)
+func (se *stdoutExporter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	return se.writeEvents(se.events.Logs(ctx, ld).Events)
 }
 
-func (se *stdoutExporter) ConsumeTraces(_ context.Context, td ptrace.Traces) error {
-	toOtelAttrs := translator.DefaultHecToOtelAttrs()
-
-	var errs []error
-	for i := 0; i < td.ResourceSpans().Len(); i++ {
-		rs := td.ResourceSpans().At(i)
-		r := rs.Resource()
-		for j := 0; j < rs.ScopeSpans().Len(); j++ {
-			ss := rs.ScopeSpans().At(j)
-			for k := 0; k < ss.Spans().Len(); k++ {
-				span := ss.Spans().At(k)
-				b, err := json.Marshal(translator.SpanToSplunkEvent(r, span, toOtelAttrs, "", "", ""))
-				if err != nil {
-					errs = append(errs, err)
-				} else {
-					if err = se.writeToStdout(b); err != nil {
-						errs = append(errs, err)
-					}
-				}
-			}
-		}
-	}
-	return errors.Join(errs...)
+func (se *stdoutExporter) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	return se.writeEvents(se.events.Traces(ctx, td).Events)
 }
 
-func (se *stdoutExporter) ConsumeMetrics(_ context.Context, md pmetric.Metrics) error {
-	toOtelAttrs := translator.DefaultHecToOtelAttrs()
+func (se *stdoutExporter) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	return se.writeEvents(se.events.Metrics(ctx, md).Events)
+}
 
+// writeEvents writes each event to stdout in the configured streaming mode, one event per line.
+func (se *stdoutExporter) writeEvents(events []*translator.Event) error {
 	var errs []error
-	for i := 0; i < md.ResourceMetrics().Len(); i++ {
-		rm := md.ResourceMetrics().At(i)
-		r := rm.Resource()
-		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
-			sm := rm.ScopeMetrics().At(j)
-			for k := 0; k < sm.Metrics().Len(); k++ {
-				m := sm.Metrics().At(k)
-				for _, result := range translator.MetricToSplunkEvent(r, m, se.TelemetrySettings.Logger, toOtelAttrs, "", "", "") {
-					b, err := json.Marshal(result)
-					if err != nil {
-						errs = append(errs, err)
-					} else {
-						if err = se.writeToStdout(b); err != nil {
-							errs = append(errs, err)
-						}
-					}
-				}
-
-			}
+	for _, event := range events {
+		b, err := se.encode(event)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err = se.writeToStdout(b); err != nil {
+			errs = append(errs, err)
 		}
 	}
 	return errors.Join(errs...)
 }
 
 func (se *stdoutExporter) writeToStdout(b []byte) error {
+	if se.config.Output != nil {
+		_, err := se.config.Output.Write(append(b, '\n'))
+		return err
+	}
 	return stdoutWriter(b)
 }
 
@@ -4,12 +4,15 @@
 package stdoutexporter
 
 import (
+	"bytes"
 	"encoding/hex"
 	"os"
 	"testing"
 
 	"github.com/splunk/otlp2splunk/internal/testutils"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configoptional"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -99,9 +102,19 @@ type testCfg struct {
 	config                 *cfg
 	startTime              string
 	telType                telemetryType
+	streamingMode          string
 	expectedResultFilePath string
 }
 
+func exporterConfig(test testCfg) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.StreamingMode = test.streamingMode
+	if test.streamingMode == StreamingModeXML {
+		cfg.Stanza = "splunk-connect-for-otlp://test"
+	}
+	return cfg
+}
+
 func logsTest(t *testing.T, test testCfg) {
 	settings := exportertest.NewNopSettings(exportertest.NopType)
 	var logs plog.Logs
@@ -111,7 +124,7 @@ func logsTest(t *testing.T, test testCfg) {
 		logs = prepareLogs()
 	}
 
-	exporter, err := newLogsExporter(t.Context(), settings, createDefaultConfig())
+	exporter, err := newLogsExporter(t.Context(), settings, exporterConfig(test))
 	require.NoError(t, err)
 	err = exporter.Start(t.Context(), componenttest.NewNopHost())
 	require.NoError(t, err)
@@ -130,7 +143,7 @@ func metricsTest(t *testing.T, test testCfg) {
 	settings := exportertest.NewNopSettings(exportertest.NopType)
 	metricData := prepareMetricsData(test.config.event)
 
-	exporter, err := newMetricsExporter(t.Context(), settings, createDefaultConfig())
+	exporter, err := newMetricsExporter(t.Context(), settings, exporterConfig(test))
 	require.NoError(t, err)
 	err = exporter.Start(t.Context(), componenttest.NewNopHost())
 	require.NoError(t, err)
@@ -149,7 +162,7 @@ func tracesTest(t *testing.T, test testCfg) {
 	settings := exportertest.NewNopSettings(exportertest.NopType)
 	tracesData := prepareTracesData(test.config.index, test.config.source, test.config.sourcetype)
 
-	exporter, err := newTracesExporter(t.Context(), settings, createDefaultConfig())
+	exporter, err := newTracesExporter(t.Context(), settings, exporterConfig(test))
 	require.NoError(t, err)
 	err = exporter.Start(t.Context(), componenttest.NewNopHost())
 	require.NoError(t, err)
@@ -182,6 +195,7 @@ func TestSplunkHecExporter(t *testing.T) {
 			},
 			startTime:              "-3h@h",
 			telType:                logsType,
+			streamingMode:          StreamingModeSimple,
 			expectedResultFilePath: "./testdata/expected_hec_log.json",
 		},
 		{
@@ -194,6 +208,7 @@ func TestSplunkHecExporter(t *testing.T) {
 			},
 			startTime:              "-1m@m",
 			telType:                logsType,
+			streamingMode:          StreamingModeSimple,
 			expectedResultFilePath: "./testdata/expected_hec_log_non_default_index.json",
 		},
 		{
@@ -206,6 +221,7 @@ func TestSplunkHecExporter(t *testing.T) {
 			},
 			startTime:              "",
 			telType:                metricsType,
+			streamingMode:          StreamingModeSimple,
 			expectedResultFilePath: "./testdata/expected_hec_metric.json",
 		},
 		{
@@ -218,8 +234,42 @@ func TestSplunkHecExporter(t *testing.T) {
 			},
 			startTime:              "-1m@m",
 			telType:                tracesType,
+			streamingMode:          StreamingModeSimple,
 			expectedResultFilePath: "./testdata/expected_hec_trace.json",
 		},
+		{
+			name: "XML streaming - logs",
+			config: &cfg{
+				event:      "This is my new event! And some number 101",
+				index:      eventIndex,
+				source:     "otel-source",
+				sourcetype: "sck-otel-st",
+			},
+			telType:                logsType,
+			streamingMode:          StreamingModeXML,
+			expectedResultFilePath: "./testdata/expected_xml_log.xml",
+		},
+		{
+			name: "XML streaming - metrics",
+			config: &cfg{
+				event: "test.metric",
+				index: metricIndex,
+			},
+			telType:                metricsType,
+			streamingMode:          StreamingModeXML,
+			expectedResultFilePath: "./testdata/expected_xml_metric.xml",
+		},
+		{
+			name: "XML streaming - traces",
+			config: &cfg{
+				index:      traceIndex,
+				source:     "trace-source",
+				sourcetype: "trace-sourcetype",
+			},
+			telType:                tracesType,
+			streamingMode:          StreamingModeXML,
+			expectedResultFilePath: "./testdata/expected_xml_trace.xml",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -262,3 +312,55 @@ func initSpan(name string, ts pcommon.Timestamp, span ptrace.Span) {
 	spanEvent.SetName("myEvent")
 	spanEvent.SetTimestamp(ts + 3)
 }
+
+func TestDefaultsFromConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.StreamingMode = StreamingModeSimple
+	cfg.Index = "otlp"
+	cfg.SourceType = "otlp:logs"
+	cfg.Source = "otlp-input"
+	cfg.Host = "forwarder-1"
+
+	logs := plog.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	records.AppendEmpty().Body().SetStr("defaults")
+	overridden := records.AppendEmpty()
+	overridden.Body().SetStr("overridden")
+	overridden.Attributes().PutStr(testutils.DefaultIndexLabel, "other")
+	overridden.Attributes().PutStr(testutils.DefaultSourceTypeLabel, "other-st")
+	overridden.Attributes().PutStr(testutils.DefaultSourceLabel, "other-source")
+	overridden.Attributes().PutStr("host.name", "myhost")
+
+	exporter, err := newLogsExporter(t.Context(), exportertest.NewNopSettings(exportertest.NopType), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Start(t.Context(), componenttest.NewNopHost()))
+	out := testutils.CaptureStdout(t, func() {
+		err = exporter.ConsumeLogs(t.Context(), logs)
+	})
+	require.NoError(t, err)
+	require.Equal(t, `{"event":"defaults","host":"forwarder-1","source":"otlp-input","sourcetype":"otlp:logs","index":"otlp"}
+{"event":"overridden","host":"myhost","source":"other-source","sourcetype":"other-st","index":"other"}
+`, out)
+}
+
+func TestWritesToOutput(t *testing.T) {
+	var output bytes.Buffer
+	cfg := createDefaultConfig().(*Config)
+	cfg.QueueBatchConfig = configoptional.None[exporterhelper.QueueBatchConfig]()
+	cfg.StreamingMode = StreamingModeSimple
+	cfg.Output = &output
+
+	logs := plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("to output")
+
+	exporter, err := newLogsExporter(t.Context(), exportertest.NewNopSettings(exportertest.NopType), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Start(t.Context(), componenttest.NewNopHost()))
+	out := testutils.CaptureStdout(t, func() {
+		err = exporter.ConsumeLogs(t.Context(), logs)
+	})
+	require.NoError(t, err)
+	require.NoError(t, exporter.Shutdown(t.Context()))
+	require.Empty(t, out)
+	require.Equal(t, "{\"event\":\"to output\",\"host\":\"unknown\"}\n", output.String())
+}
@@ -4,6 +4,7 @@
 package stdoutexporter
 
 import (
+	"github.com/splunk/otlp2splunk/internal/exporter/splunkevent"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configoptional"
 	"go.opentelemetry.io/collector/exporter"
@@ -32,5 +33,9 @@ func NewFactory() exporter.Factory {
 func createDefaultConfig() component.Config {
 	return &Config{
 		QueueBatchConfig: configoptional.Some(exporterhelper.NewDefaultQueueConfig()),
+		Config: splunkevent.Config{
+			IndexViolationAction: splunkevent.IndexViolationDrop,
+		},
+		StreamingMode: StreamingModeXML,
 	}
 }
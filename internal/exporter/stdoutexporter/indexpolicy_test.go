@@ -0,0 +1,135 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package stdoutexporter
+
+import (
+	"testing"
+
+	"github.com/splunk/otlp2splunk/internal/exporter/splunkevent"
+	"github.com/splunk/otlp2splunk/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func prepareRoutedLogs(indexes ...string) plog.Logs {
+	logs := plog.NewLogs()
+	for _, index := range indexes {
+		rl := logs.ResourceLogs().AppendEmpty()
+		if index != "" {
+			rl.Resource().Attributes().PutStr(testutils.DefaultIndexLabel, index)
+		}
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("to " + index)
+	}
+	return logs
+}
+
+func TestIndexViolations(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     string
+		expected   string
+		violations int64
+		rejected   bool
+	}{
+		{
+			name:   "drop",
+			action: splunkevent.IndexViolationDrop,
+			expected: `{"event":"to ","host":"unknown","index":"otlp"}
+{"event":"to otlp_app","host":"unknown","index":"otlp_app"}
+`,
+			violations: 1,
+		},
+		{
+			name:   "default",
+			action: splunkevent.IndexViolationDefault,
+			expected: `{"event":"to ","host":"unknown","index":"otlp"}
+{"event":"to otlp_app","host":"unknown","index":"otlp_app"}
+{"event":"to _audit","host":"unknown","index":"otlp"}
+`,
+			violations: 1,
+		},
+		{
+			name:       "reject",
+			action:     splunkevent.IndexViolationReject,
+			violations: 3,
+			rejected:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			settings := exportertest.NewNopSettings(exportertest.NopType)
+			settings.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.QueueBatchConfig.GetOrInsertDefault().WaitForResult = true
+			cfg.StreamingMode = StreamingModeSimple
+			cfg.Index = "otlp"
+			cfg.AllowedIndexes = []string{"main", "otlp_.*"}
+			cfg.IndexViolationAction = tt.action
+			require.NoError(t, cfg.Validate())
+
+			exporter, err := newLogsExporter(t.Context(), settings, cfg)
+			require.NoError(t, err)
+			require.NoError(t, exporter.Start(t.Context(), componenttest.NewNopHost()))
+			out := testutils.CaptureStdout(t, func() {
+				err = exporter.ConsumeLogs(t.Context(), prepareRoutedLogs("", "otlp_app", "_audit"))
+			})
+
+			if tt.rejected {
+				require.ErrorContains(t, err, `index "_audit" is not allowed`)
+				require.True(t, consumererror.IsPermanent(err))
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expected, out)
+
+			var rm metricdata.ResourceMetrics
+			require.NoError(t, reader.Collect(t.Context(), &rm))
+			var violations []metricdata.Metrics
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					if m.Name == "otlp_input_index_violations" {
+						violations = append(violations, m)
+					}
+				}
+			}
+			require.Len(t, violations, 1)
+			require.Equal(t, tt.violations, violations[0].Data.(metricdata.Sum[int64]).DataPoints[0].Value)
+		})
+	}
+}
+
+func TestRejectMetricsIndexViolations(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.AllowedIndexes = []string{"metrics"}
+	cfg.IndexViolationAction = splunkevent.IndexViolationReject
+
+	exporter, err := newMetricsExporter(t.Context(), exportertest.NewNopSettings(exportertest.NopType), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Start(t.Context(), componenttest.NewNopHost()))
+
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().Resource().Attributes().PutStr(testutils.DefaultIndexLabel, "_internal")
+	err = exporter.ConsumeMetrics(t.Context(), md)
+	require.ErrorContains(t, err, `index "_internal" is not allowed`)
+	require.True(t, consumererror.IsPermanent(err))
+}
+
+func TestConfigValidateIndexPolicy(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.AllowedIndexes = []string{"main", "otlp_("}
+	require.ErrorContains(t, cfg.Validate(), `invalid allowed index "otlp_("`)
+
+	cfg.AllowedIndexes = nil
+	cfg.IndexViolationAction = "ignore"
+	require.EqualError(t, cfg.Validate(), `index_violation_action "ignore" is not supported, it must be one of drop, default or reject`)
+}
@@ -0,0 +1,69 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package stdoutexporter
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	"github.com/goccy/go-json"
+	translator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/splunk"
+)
+
+const (
+	// StreamingModeXML writes each event as a Splunk XML streaming <stream><event> element carrying its metadata,
+	// the streaming mode of the input scheme.
+	StreamingModeXML = "xml"
+	// StreamingModeSimple writes each event as a line of HEC JSON, for the convert command.
+	StreamingModeSimple = "simple"
+)
+
+// xmlStream is a Splunk XML streaming document holding a single event.
+type xmlStream struct {
+	XMLName xml.Name `xml:"stream"`
+	Event   xmlEvent `xml:"event"`
+}
+
+type xmlEvent struct {
+	Stanza     string  `xml:"stanza,attr,omitempty"`
+	Time       string  `xml:"time,omitempty"`
+	Index      string  `xml:"index,omitempty"`
+	SourceType string  `xml:"sourcetype,omitempty"`
+	Host       string  `xml:"host,omitempty"`
+	Source     string  `xml:"source,omitempty"`
+	Data       xmlData `xml:"data"`
+}
+
+type xmlData struct {
+	Text string `xml:",cdata"`
+}
+
+// xmlPayload is the part of a HEC event written in the <data> element. The metadata has elements of its own.
+type xmlPayload struct {
+	Event  any            `json:"event"`
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// encode returns the event as written in the configured streaming mode.
+func (se *stdoutExporter) encode(event *translator.Event) ([]byte, error) {
+	if se.config.StreamingMode != StreamingModeXML {
+		return json.Marshal(event)
+	}
+	data, err := json.Marshal(xmlPayload{Event: event.Event, Fields: event.Fields})
+	if err != nil {
+		return nil, err
+	}
+	stream := xmlStream{Event: xmlEvent{
+		Stanza:     se.config.Stanza,
+		Index:      event.Index,
+		SourceType: event.SourceType,
+		Host:       event.Host,
+		Source:     event.Source,
+		Data:       xmlData{Text: string(data)},
+	}}
+	if event.Time != 0 {
+		stream.Event.Time = strconv.FormatFloat(event.Time, 'f', -1, 64)
+	}
+	return xml.Marshal(stream)
+}
@@ -0,0 +1,48 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package stdoutexporter
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/splunk/otlp2splunk/internal/testutils"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestXMLStreamingRoundTrip(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.StreamingMode = StreamingModeXML
+	cfg.Index = "otlp"
+
+	logs := plog.NewLogs()
+	record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.Body().SetStr("<not markup> ]]> & multi\nline")
+	record.SetTimestamp(pcommon.Timestamp(1700000000123000000))
+
+	exporter, err := newLogsExporter(t.Context(), exportertest.NewNopSettings(exportertest.NopType), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Start(t.Context(), componenttest.NewNopHost()))
+	out := testutils.CaptureStdout(t, func() {
+		err = exporter.ConsumeLogs(t.Context(), logs)
+	})
+	require.NoError(t, err)
+
+	var stream xmlStream
+	require.NoError(t, xml.Unmarshal([]byte(out), &stream))
+	require.Equal(t, "1700000000.123", stream.Event.Time)
+	require.Equal(t, "otlp", stream.Event.Index)
+	require.Equal(t, `{"event":"\u003cnot markup\u003e ]]\u003e \u0026 multi\nline"}`, stream.Event.Data.Text)
+}
+
+func TestConfigValidateStreamingMode(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+	cfg.StreamingMode = "json"
+	require.EqualError(t, cfg.Validate(), `streaming_mode "json" is not supported, it must be either simple or xml`)
+}
@@ -0,0 +1,30 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenauthextension
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config/configopaque"
+)
+
+type Config struct {
+	// Tokens accepted in the Authorization header of incoming requests.
+	Tokens []configopaque.String `mapstructure:"tokens"`
+	// Scheme is the authorization scheme the tokens are presented with: Bearer when empty, or Splunk for the
+	// clients of the HTTP Event Collector.
+	Scheme string `mapstructure:"scheme"`
+}
+
+func (c *Config) Validate() error {
+	if len(c.Tokens) == 0 {
+		return errors.New("at least one token must be configured")
+	}
+	for _, token := range c.Tokens {
+		if token == "" {
+			return errors.New("tokens must not be empty")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenauthextension
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/extensionauth"
+)
+
+const defaultScheme = "Bearer"
+
+var (
+	_ extension.Extension  = &tokenAuth{}
+	_ extensionauth.Server = &tokenAuth{}
+
+	errMissingToken = errors.New("missing token in the authorization header")
+	errInvalidToken = errors.New("invalid token")
+)
+
+// tokenAuth authenticates requests carrying one of the configured tokens in an "Authorization: <scheme>" header.
+type tokenAuth struct {
+	tokens [][]byte
+	// prefix is the scheme of the authorization header, followed by a space.
+	prefix string
+}
+
+func newTokenAuth(cfg *Config) *tokenAuth {
+	tokens := make([][]byte, 0, len(cfg.Tokens))
+	for _, token := range cfg.Tokens {
+		tokens = append(tokens, []byte(token))
+	}
+	scheme := cfg.Scheme
+	if scheme == "" {
+		scheme = defaultScheme
+	}
+	return &tokenAuth{tokens: tokens, prefix: scheme + " "}
+}
+
+func (ta *tokenAuth) Start(context.Context, component.Host) error {
+	return nil
+}
+
+func (ta *tokenAuth) Shutdown(context.Context) error {
+	return nil
+}
+
+// Authenticate checks the authorization header of HTTP requests and the authorization metadata of gRPC calls.
+func (ta *tokenAuth) Authenticate(ctx context.Context, sources map[string][]string) (context.Context, error) {
+	token, ok := ta.token(sources)
+	if !ok {
+		return ctx, errMissingToken
+	}
+	for _, t := range ta.tokens {
+		if subtle.ConstantTimeCompare(t, []byte(token)) == 1 {
+			return ctx, nil
+		}
+	}
+	return ctx, errInvalidToken
+}
+
+// token looks up the authorization header regardless of its case: HTTP headers are canonicalized
+// while gRPC metadata keys are lowercase.
+func (ta *tokenAuth) token(sources map[string][]string) (string, bool) {
+	for k, values := range sources {
+		if !strings.EqualFold(k, "authorization") {
+			continue
+		}
+		for _, v := range values {
+			if len(v) > len(ta.prefix) && strings.EqualFold(v[:len(ta.prefix)], ta.prefix) {
+				return v[len(ta.prefix):], true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,80 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenauthextension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/extension/extensionauth"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+)
+
+func TestAuthenticate(t *testing.T) {
+	cfg := &Config{Tokens: []configopaque.String{"first", "second"}}
+	require.NoError(t, cfg.Validate())
+
+	ext, err := NewFactory().Create(t.Context(), extensiontest.NewNopSettings(NewFactory().Type()), cfg)
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(t.Context(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, ext.Shutdown(t.Context()))
+	}()
+	server := ext.(extensionauth.Server)
+
+	tests := []struct {
+		name    string
+		sources map[string][]string
+		err     error
+	}{
+		{
+			name:    "http header",
+			sources: map[string][]string{"Authorization": {"Bearer first"}},
+		},
+		{
+			name:    "grpc metadata",
+			sources: map[string][]string{"authorization": {"bearer second"}},
+		},
+		{
+			name:    "missing header",
+			sources: map[string][]string{"Content-Type": {"application/json"}},
+			err:     errMissingToken,
+		},
+		{
+			name:    "other scheme",
+			sources: map[string][]string{"Authorization": {"Splunk first"}},
+			err:     errMissingToken,
+		},
+		{
+			name:    "unknown token",
+			sources: map[string][]string{"Authorization": {"Bearer third"}},
+			err:     errInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.Authenticate(t.Context(), tt.sources)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestAuthenticateSplunkScheme(t *testing.T) {
+	cfg := &Config{Tokens: []configopaque.String{"first"}, Scheme: "Splunk"}
+	ext, err := NewFactory().Create(t.Context(), extensiontest.NewNopSettings(NewFactory().Type()), cfg)
+	require.NoError(t, err)
+	server := ext.(extensionauth.Server)
+
+	_, err = server.Authenticate(t.Context(), map[string][]string{"Authorization": {"Splunk first"}})
+	require.NoError(t, err)
+	_, err = server.Authenticate(t.Context(), map[string][]string{"Authorization": {"Bearer first"}})
+	require.ErrorIs(t, err, errMissingToken)
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.EqualError(t, (&Config{}).Validate(), "at least one token must be configured")
+	require.EqualError(t, (&Config{Tokens: []configopaque.String{""}}).Validate(), "tokens must not be empty")
+}
@@ -0,0 +1,37 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenauthextension
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+)
+
+// This file implements factory for the token authenticator extension.
+
+const (
+	typeStr        = "tokenauth"
+	stabilityLevel = component.StabilityLevelDevelopment
+)
+
+// NewFactory creates a factory for the token authenticator extension.
+func NewFactory() extension.Factory {
+	return extension.NewFactory(
+		component.MustNewType(typeStr),
+		createDefaultConfig,
+		createExtension,
+		stabilityLevel,
+	)
+}
+
+// createDefaultConfig creates the default configuration for the token authenticator extension.
+func createDefaultConfig() component.Config {
+	return &Config{}
+}
+
+func createExtension(_ context.Context, _ extension.Settings, cfg component.Config) (extension.Extension, error) {
+	return newTokenAuth(cfg.(*Config)), nil
+}
@@ -0,0 +1,165 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// StanzaScheme prefixes the name of the input stanzas, as Splunk sends them to the input.
+const StanzaScheme = "splunk-connect-for-otlp://"
+
+// FileConfig is the configuration of the inputs served by a standalone daemon, outside splunkd.
+type FileConfig struct {
+	// Host is the host of events lacking the host.name attribute, when an input sets host to $decideOnStartup.
+	// Defaults to the name of the machine.
+	Host string `yaml:"host"`
+	// CheckpointDir is the directory the inputs persist their state to, like the checkpoint_dir Splunk provides.
+	CheckpointDir string `yaml:"checkpoint_dir"`
+	// Secrets holds the values of the secrets named by the auth_tokens and hec_token parameters.
+	Secrets map[string]string `yaml:"secrets"`
+	// Inputs maps the name of each input to its parameters, named and valued like the parameters of a stanza.
+	// The values are kept as written, so 1.0 stays 1.0 rather than becoming the number 1.
+	Inputs map[string]map[string]yaml.Node `yaml:"inputs"`
+}
+
+// invalidNameChars matches the characters the name of an input cannot contain.
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// envVarPattern matches the references to environment variables expanded in configuration files.
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// LoadFileConfig reads the configuration file at path, replacing ${NAME} in its values with the value of the
+// environment variable NAME.
+func LoadFileConfig(path string) (FileConfig, error) {
+	var config FileConfig
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	var root yaml.Node
+	if err = yaml.Unmarshal(b, &root); err != nil {
+		return config, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+	var missing []string
+	expandEnv(&root, &missing)
+	if len(missing) > 0 {
+		return config, fmt.Errorf("environment variables %s referenced by %s are not set", strings.Join(missing, ", "), path)
+	}
+	if err = root.Decode(&config); err != nil {
+		return config, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// expandEnv replaces ${NAME} with the value of the environment variable NAME in the values under n. Values are
+// expanded once parsed, so the value of a variable, such as a secret, is taken as is and never parsed as YAML.
+// The names of the variables which are not set are added to missing.
+func expandEnv(n *yaml.Node, missing *[]string) {
+	if n.Kind == yaml.ScalarNode && envVarPattern.MatchString(n.Value) {
+		n.Value = envVarPattern.ReplaceAllStringFunc(n.Value, func(ref string) string {
+			name := envVarPattern.FindStringSubmatch(ref)[1]
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				*missing = append(*missing, name)
+			}
+			return value
+		})
+		n.Tag = "!!str"
+	}
+	for i, child := range n.Content {
+		// Keys are names, not values.
+		if n.Kind == yaml.MappingNode && i%2 == 0 {
+			continue
+		}
+		expandEnv(child, missing)
+	}
+}
+
+// XMLInputs returns the inputs of the configuration as Splunk would send them to the input, so they are
+// validated and applied exactly like stanzas. Inputs are sorted by name.
+func (c FileConfig) XMLInputs() ([]XMLInput, error) {
+	if len(c.Inputs) == 0 {
+		return nil, errors.New("the configuration does not define any input")
+	}
+	names := make([]string, 0, len(c.Inputs))
+	for name := range c.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	host := c.Host
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	inputs := make([]XMLInput, 0, len(names))
+	var errs []error
+	for _, name := range names {
+		if name == "" || invalidNameChars.MatchString(name) {
+			errs = append(errs, fmt.Errorf("input name %q must only contain letters, digits, _, . and -", name))
+			continue
+		}
+		stanza := XMLStanza{Name: StanzaScheme + name}
+		for param, value := range c.Inputs[name] {
+			stanza.Params = append(stanza.Params, XMLParam{Name: param, Value: nodeValue(value)})
+		}
+		sort.Slice(stanza.Params, func(i, j int) bool {
+			return stanza.Params[i].Name < stanza.Params[j].Name
+		})
+		inputs = append(inputs, XMLInput{
+			ServerHost:    host,
+			CheckpointDir: c.CheckpointDir,
+			Configuration: XMLConfig{Stanza: stanza},
+			Secrets:       c.Secrets,
+		})
+	}
+	return inputs, errors.Join(errs...)
+}
+
+// nodeValue returns the value of a parameter as written in a stanza. Scalars keep their text, and lists are
+// joined with commas.
+func nodeValue(value yaml.Node) string {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		if value.Tag == "!!null" {
+			return ""
+		}
+		return value.Value
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(value.Content))
+		for _, item := range value.Content {
+			items = append(items, nodeValue(*item))
+		}
+		return strings.Join(items, ",")
+	default:
+		return value.Value
+	}
+}
+
+// CheckListeners reports an error for every port several inputs listen on.
+func CheckListeners(inputs []Settings) error {
+	var errs []error
+	seen := map[Listener]string{}
+	for _, settings := range inputs {
+		for _, l := range settings.Listeners() {
+			if l.Port == 0 {
+				continue
+			}
+			key := Listener{Network: l.Network, Port: l.Port}
+			if other, ok := seen[key]; ok {
+				errs = append(errs, fmt.Errorf("%s of %s and %s both listen on %s port %d", l.Param, settings.Name, other, l.Network, l.Port))
+				continue
+			}
+			seen[key] = settings.Name
+		}
+	}
+	return errors.Join(errs...)
+}
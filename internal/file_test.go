@@ -0,0 +1,148 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFileConfig(t *testing.T) {
+	t.Setenv("OTLP_EDGE_TOKEN", "s3cr3t")
+	path := writeConfigFile(t, `
+host: edge-1
+checkpoint_dir: /var/lib/splunk-connect-for-otlp
+secrets:
+  edge: ${OTLP_EDGE_TOKEN}
+inputs:
+  team_b:
+    grpc_port: 5317
+    http_port: 5318
+    index: team_b
+  team_a:
+    host: $decideOnStartup
+    allowed_indexes: [main, "otlp_.*"]
+    auth_tokens: edge
+    persistent_queue: true
+`)
+
+	config, err := LoadFileConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"edge": "s3cr3t"}, config.Secrets)
+
+	inputs, err := config.XMLInputs()
+	require.NoError(t, err)
+	require.Len(t, inputs, 2)
+	require.Equal(t, XMLStanza{Name: "splunk-connect-for-otlp://team_a", Params: []XMLParam{
+		{Name: "allowed_indexes", Value: "main,otlp_.*"},
+		{Name: "auth_tokens", Value: "edge"},
+		{Name: "host", Value: "$decideOnStartup"},
+		{Name: "persistent_queue", Value: "true"},
+	}}, inputs[0].Configuration.Stanza)
+
+	settings, err := inputs[0].Extract()
+	require.NoError(t, err)
+	require.Equal(t, "edge-1", settings.Host)
+	require.Equal(t, []string{"main", "otlp_.*"}, settings.AllowedIndexes)
+	require.Equal(t, filepath.Join("/var/lib/splunk-connect-for-otlp", "queue", "team_a"), settings.QueueDirectory())
+	tokens, err := inputs[0].FetchSecrets(context.Background(), "", settings.AuthTokens)
+	require.NoError(t, err)
+	require.Equal(t, []string{"s3cr3t"}, tokens)
+
+	settings, err = inputs[1].Extract()
+	require.NoError(t, err)
+	require.Equal(t, "splunk-connect-for-otlp://team_b", settings.Name)
+	require.Equal(t, 5317, settings.GRPCPort)
+	require.Equal(t, "team_b", settings.Logs.Index)
+
+	_, err = inputs[1].FetchSecrets(context.Background(), "", []string{"missing"})
+	require.EqualError(t, err, `secret "missing" not found`)
+}
+
+func TestLoadFileConfigExpandsValues(t *testing.T) {
+	t.Setenv("OTLP_EDGE_TOKEN", "s3 #cr*&!{[3t")
+	t.Setenv("OTLP_PORT", "5317")
+	path := writeConfigFile(t, `
+# Set OTLP_EDGE_TOKEN, or ${OTLP_UNSET_TOKEN} in older setups.
+secrets:
+  edge: ${OTLP_EDGE_TOKEN}
+inputs:
+  edge:
+    grpc_port: ${OTLP_PORT}
+    source: "${OTLP_PORT}/${OTLP_PORT}"
+`)
+
+	config, err := LoadFileConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"edge": "s3 #cr*&!{[3t"}, config.Secrets)
+	inputs, err := config.XMLInputs()
+	require.NoError(t, err)
+	require.Equal(t, []XMLParam{
+		{Name: "grpc_port", Value: "5317"},
+		{Name: "source", Value: "5317/5317"},
+	}, inputs[0].Configuration.Stanza.Params)
+}
+
+func TestLoadFileConfigKeepsScalarText(t *testing.T) {
+	path := writeConfigFile(t, `
+inputs:
+  edge:
+    cert_file: /etc/otlp/cert.pem
+    key_file: /etc/otlp/key.pem
+    min_version: 1.0
+    source: 0x10
+    host:
+`)
+
+	config, err := LoadFileConfig(path)
+	require.NoError(t, err)
+	inputs, err := config.XMLInputs()
+	require.NoError(t, err)
+	require.Equal(t, []XMLParam{
+		{Name: "cert_file", Value: "/etc/otlp/cert.pem"},
+		{Name: "host", Value: ""},
+		{Name: "key_file", Value: "/etc/otlp/key.pem"},
+		{Name: "min_version", Value: "1.0"},
+		{Name: "source", Value: "0x10"},
+	}, inputs[0].Configuration.Stanza.Params)
+
+	settings, err := inputs[0].Extract()
+	require.NoError(t, err)
+	require.Equal(t, "1.0", settings.TLS.MinVersion)
+}
+
+func TestLoadFileConfigErrors(t *testing.T) {
+	_, err := LoadFileConfig(writeConfigFile(t, "inputs:\n  edge:\n    hec_token: ${OTLP_UNSET_TOKEN}\n"))
+	require.ErrorContains(t, err, "environment variables OTLP_UNSET_TOKEN referenced by")
+
+	config, err := LoadFileConfig(writeConfigFile(t, "inputs:\n  team a:\n    index: main\n"))
+	require.NoError(t, err)
+	_, err = config.XMLInputs()
+	require.EqualError(t, err, `input name "team a" must only contain letters, digits, _, . and -`)
+
+	_, err = FileConfig{}.XMLInputs()
+	require.EqualError(t, err, "the configuration does not define any input")
+}
+
+func TestCheckListeners(t *testing.T) {
+	a, err := XMLStanza{Name: "splunk-connect-for-otlp://a"}.Settings()
+	require.NoError(t, err)
+	b, err := XMLStanza{Name: "splunk-connect-for-otlp://b", Params: []XMLParam{{Name: "grpc_port", Value: "5317"}}}.Settings()
+	require.NoError(t, err)
+
+	require.EqualError(t, CheckListeners([]Settings{a, b}),
+		"http_port of splunk-connect-for-otlp://b and splunk-connect-for-otlp://a both listen on tcp port 4318")
+	b.HTTPPort = 5318
+	require.NoError(t, CheckListeners([]Settings{a, b}))
+}
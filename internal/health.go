@@ -0,0 +1,121 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.uber.org/zap"
+)
+
+type componentHealth struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type healthResponse struct {
+	Components map[string]componentHealth `json:"components,omitempty"`
+	Status     string                     `json:"status"`
+}
+
+// HealthServer serves the liveness and readiness of the input over HTTP.
+// The input is ready once every component reported it started successfully.
+type HealthServer struct {
+	host   *TTYHost
+	logger *zap.Logger
+	server *http.Server
+	mux    *http.ServeMux
+}
+
+// NewHealthServer returns a health server listening on address and port.
+func NewHealthServer(address string, port int, host *TTYHost, logger *zap.Logger) *HealthServer {
+	s := &HealthServer{host: host, logger: logger, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/health/live", s.live)
+	s.mux.HandleFunc("/health/ready", s.ready)
+	s.server = &http.Server{
+		Addr:              net.JoinHostPort(address, strconv.Itoa(port)),
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// HandleLogLevel serves the log level of the input on /log/level: GET returns the level, PUT changes it, with a
+// body like {"level":"debug"}. The endpoint is not authenticated, so it only answers clients on the loopback
+// interface, whatever the listening address of the health server.
+func (s *HealthServer) HandleLogLevel(level zap.AtomicLevel) {
+	s.mux.Handle("/log/level", loopbackOnly(level))
+}
+
+// loopbackOnly forbids the requests of clients which are not on the loopback interface to h.
+func loopbackOnly(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+			http.Error(w, "the log level can only be changed from the local host", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Start listens and serves health requests in the background.
+func (s *HealthServer) Start() error {
+	l, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := s.server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Health server stopped", zap.Error(err))
+		}
+	}()
+	s.logger.Info("Health server started", zap.String("endpoint", l.Addr().String()))
+	return nil
+}
+
+// Shutdown stops the health server.
+func (s *HealthServer) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+func (s *HealthServer) live(w http.ResponseWriter, _ *http.Request) {
+	writeHealth(w, http.StatusOK, healthResponse{Status: "alive"})
+}
+
+func (s *HealthServer) ready(w http.ResponseWriter, _ *http.Request) {
+	response := healthResponse{Status: "ready", Components: map[string]componentHealth{}}
+	statuses := s.host.ComponentStatuses()
+	ready := len(statuses) > 0
+	for id, event := range statuses {
+		health := componentHealth{Status: event.Status().String(), Timestamp: event.Timestamp()}
+		if event.Err() != nil {
+			health.Error = event.Err().Error()
+		}
+		response.Components[id.String()] = health
+		if event.Status() != componentstatus.StatusOK {
+			ready = false
+		}
+	}
+	if !ready {
+		response.Status = "not_ready"
+		writeHealth(w, http.StatusServiceUnavailable, response)
+		return
+	}
+	writeHealth(w, http.StatusOK, response)
+}
+
+func writeHealth(w http.ResponseWriter, status int, response healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,84 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.uber.org/zap"
+)
+
+func getHealth(t *testing.T, s *HealthServer, path string) (int, healthResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+	var response healthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return rec.Code, response
+}
+
+func TestHealthServer(t *testing.T) {
+	h := &TTYHost{ErrStatus: make(chan error, 1)}
+	s := NewHealthServer("127.0.0.1", 0, h, zap.NewNop())
+	receiver := component.MustNewID("otlp")
+	exporter := component.MustNewIDWithName("stdout", "logs")
+
+	code, response := getHealth(t, s, "/health/live")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "alive", response.Status)
+
+	code, response = getHealth(t, s, "/health/ready")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "not_ready", response.Status)
+
+	h.ReportComponentStatus(receiver, componentstatus.NewEvent(componentstatus.StatusOK))
+	h.ReportComponentStatus(exporter, componentstatus.NewEvent(componentstatus.StatusOK))
+	code, response = getHealth(t, s, "/health/ready")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "ready", response.Status)
+	require.Equal(t, "StatusOK", response.Components["otlp"].Status)
+
+	h.ForComponent(exporter).(componentstatus.Reporter).Report(componentstatus.NewRecoverableErrorEvent(errors.New("stdout is closed")))
+	code, response = getHealth(t, s, "/health/ready")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "not_ready", response.Status)
+	require.Equal(t, componentHealth{
+		Status:    "StatusRecoverableError",
+		Error:     "stdout is closed",
+		Timestamp: response.Components["stdout/logs"].Timestamp,
+	}, response.Components["stdout/logs"])
+	require.Empty(t, h.ErrStatus, "recoverable errors must not stop the input")
+}
+
+func TestHealthServerLogLevel(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zap.InfoLevel)
+	s := NewHealthServer("127.0.0.1", 0, &TTYHost{}, zap.NewNop())
+	s.HandleLogLevel(level)
+
+	put := func(remoteAddr string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(`{"level":"debug"}`))
+		req.RemoteAddr = remoteAddr
+		s.mux.ServeHTTP(rec, req)
+		return rec
+	}
+	require.Equal(t, http.StatusForbidden, put("192.0.2.1:40000").Code)
+	require.Equal(t, zap.InfoLevel, level.Level())
+	require.Equal(t, http.StatusOK, put("127.0.0.1:40000").Code)
+	require.Equal(t, zap.DebugLevel, level.Level())
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/log/level", http.NoBody)
+	req.RemoteAddr = "[::1]:40000"
+	s.mux.ServeHTTP(rec, req)
+	require.JSONEq(t, `{"level":"debug"}`, rec.Body.String())
+}
@@ -16,12 +16,17 @@ import (
 var (
 	_ component.Host           = &TTYHost{}
 	_ componentstatus.Reporter = &TTYHost{}
+	_ component.Host           = &componentHost{}
+	_ componentstatus.Reporter = &componentHost{}
 )
 
 type TTYHost struct {
-	ErrStatus    chan error
-	Extensions   map[component.ID]component.Component
-	shutdownOnce sync.Once
+	ErrStatus  chan error
+	Extensions map[component.ID]component.Component
+	statuses   map[component.ID]*componentstatus.Event
+	stopping   bool
+	reportMu   sync.Mutex
+	statusMu   sync.RWMutex
 }
 
 func (t *TTYHost) Start() {
@@ -37,17 +42,75 @@ func (t *TTYHost) Wait() error {
 	return <-t.ErrStatus
 }
 
+// Report stops the input on StatusStopping, or on an error. Only the first of them stops the input: it is
+// stopping already when the next ones are reported, for example by a reload still running.
 func (t *TTYHost) Report(event *componentstatus.Event) {
+	t.reportMu.Lock()
+	defer t.reportMu.Unlock()
+	if t.stopping {
+		return
+	}
 	if event.Status() == componentstatus.StatusStopping {
-		t.shutdownOnce.Do(func() {
-			close(t.ErrStatus)
-		})
+		t.stopping = true
+		close(t.ErrStatus)
+		return
 	}
 	if event.Err() != nil {
-		t.ErrStatus <- event.Err()
+		select {
+		case t.ErrStatus <- event.Err():
+		default:
+		}
 	}
 }
 
 func (t *TTYHost) GetExtensions() map[component.ID]component.Component {
 	return t.Extensions
 }
+
+// ForComponent returns the host to start the component identified by id with, so the status it reports
+// is tracked separately from the status of other components.
+func (t *TTYHost) ForComponent(id component.ID) component.Host {
+	return &componentHost{TTYHost: t, id: id}
+}
+
+// ReportComponentStatus records the status of the component identified by id.
+func (t *TTYHost) ReportComponentStatus(id component.ID, event *componentstatus.Event) {
+	t.statusMu.Lock()
+	defer t.statusMu.Unlock()
+	if t.statuses == nil {
+		t.statuses = map[component.ID]*componentstatus.Event{}
+	}
+	t.statuses[id] = event
+}
+
+// RemoveComponentStatus forgets the status of the component identified by id, once it was removed from the input.
+func (t *TTYHost) RemoveComponentStatus(id component.ID) {
+	t.statusMu.Lock()
+	defer t.statusMu.Unlock()
+	delete(t.statuses, id)
+}
+
+// ComponentStatuses returns the last status reported by each component.
+func (t *TTYHost) ComponentStatuses() map[component.ID]*componentstatus.Event {
+	t.statusMu.RLock()
+	defer t.statusMu.RUnlock()
+	statuses := make(map[component.ID]*componentstatus.Event, len(t.statuses))
+	for id, event := range t.statuses {
+		statuses[id] = event
+	}
+	return statuses
+}
+
+// componentHost is the host of a single component.
+type componentHost struct {
+	*TTYHost
+	id component.ID
+}
+
+// Report records the status of the component. Only fatal errors stop the input.
+func (c *componentHost) Report(event *componentstatus.Event) {
+	c.ReportComponentStatus(c.id, event)
+	if event.Status() == componentstatus.StatusFatalError {
+		c.TTYHost.Report(event)
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+)
+
+func TestTTYHostReportAfterStopping(t *testing.T) {
+	h := &TTYHost{ErrStatus: make(chan error, 1)}
+	h.Report(componentstatus.NewEvent(componentstatus.StatusStopping))
+	require.NoError(t, h.Wait())
+
+	// A reload failing once the input is stopping must not stop it again.
+	host := h.ForComponent(component.MustNewID("otlp")).(componentstatus.Reporter)
+	require.NotPanics(t, func() {
+		host.Report(componentstatus.NewFatalErrorEvent(errors.New("cannot restore the receiver")))
+		h.Report(componentstatus.NewEvent(componentstatus.StatusStopping))
+	})
+}
+
+func TestTTYHostReportsFirstError(t *testing.T) {
+	h := &TTYHost{ErrStatus: make(chan error, 1)}
+	h.Report(componentstatus.NewFatalErrorEvent(errors.New("first")))
+	h.Report(componentstatus.NewFatalErrorEvent(errors.New("second")))
+	require.EqualError(t, h.Wait(), "first")
+}
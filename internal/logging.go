@@ -4,14 +4,116 @@
 package internal
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
 	"go.uber.org/zap/zapcore"
 )
 
-func CreateLogger() (*zap.Logger, error) {
+// splunkdEncoding is the name of the encoding writing log lines splunkd parses the level of.
+const splunkdEncoding = "splunkd"
+
+func init() {
+	if err := zap.RegisterEncoder(splunkdEncoding, func(zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return keyValueEncoder{zapcore.NewMapObjectEncoder()}, nil
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// CreateLogger returns the logger of an input, writing to stderr, and the level of the logger, which can be
+// changed while the input runs.
+func CreateLogger(settings LogSettings) (*zap.Logger, zap.AtomicLevel, error) {
+	level := zap.NewAtomicLevelAt(settings.Level)
 	zapCfg := zap.NewProductionConfig()
-	zapCfg.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	zapCfg.Level = level
+	if settings.Format == splunkdEncoding {
+		zapCfg.Encoding = splunkdEncoding
+		// splunkd records the stderr of the input with its own timestamp.
+		zapCfg.DisableStacktrace = true
+	}
 	zapCfg.OutputPaths = []string{"stderr"}
 	zapCfg.ErrorOutputPaths = []string{"stderr"}
-	return zapCfg.Build()
+	logger, err := zapCfg.Build()
+	return logger, level, err
+}
+
+// keyValueEncoder writes log lines the way splunkd expects them on the stderr of modular inputs: the level
+// first, so splunkd logs the line at that level in splunkd.log, then key=value pairs sorted by key.
+type keyValueEncoder struct {
+	*zapcore.MapObjectEncoder
+}
+
+var keyValuePool = buffer.NewPool()
+
+func (e keyValueEncoder) Clone() zapcore.Encoder {
+	clone := zapcore.NewMapObjectEncoder()
+	for key, value := range e.Fields {
+		clone.Fields[key] = value
+	}
+	return keyValueEncoder{clone}
+}
+
+func (e keyValueEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	enc := e.Clone().(keyValueEncoder)
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	keys := make([]string, 0, len(enc.Fields))
+	for key := range enc.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buf := keyValuePool.Get()
+	buf.AppendString(entry.Level.CapitalString())
+	buf.AppendString(" message=")
+	buf.AppendString(keyValue(entry.Message))
+	for _, key := range keys {
+		buf.AppendByte(' ')
+		buf.AppendString(key)
+		buf.AppendByte('=')
+		buf.AppendString(keyValue(enc.Fields[key]))
+	}
+	if entry.Caller.Defined {
+		buf.AppendString(" caller=")
+		buf.AppendString(entry.Caller.TrimmedPath())
+	}
+	buf.AppendByte('\n')
+	return buf, nil
+}
+
+// keyValue formats the value of a key=value pair. Strings are quoted when they would not read as a single value.
+func keyValue(value any) string {
+	var s string
+	switch v := value.(type) {
+	case string:
+		if v != "" && !strings.ContainsAny(v, " \t\r\n\"=") {
+			return v
+		}
+		return strconv.Quote(v)
+	case time.Duration:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			s = fmt.Sprint(v)
+		} else {
+			s = string(b)
+		}
+	}
+	if strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
 }
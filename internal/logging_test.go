@@ -0,0 +1,40 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestKeyValueEncoder(t *testing.T) {
+	enc := keyValueEncoder{zapcore.NewMapObjectEncoder()}
+	zap.String("stanza", "splunk-connect-for-otlp://team a").AddTo(enc)
+	child := enc.Clone()
+	zap.Int("tokens", 2).AddTo(child)
+
+	buf, err := child.EncodeEntry(zapcore.Entry{Level: zapcore.WarnLevel, Message: "Cannot capture request"}, []zapcore.Field{
+		zap.Error(errors.New(`open "capture.jsonl": permission denied`)),
+		zap.Duration("interval", time.Minute),
+		zap.Bool("mtls", true),
+		zap.String("signal", "logs"),
+		zap.String("empty", ""),
+	})
+	require.NoError(t, err)
+	require.Equal(t, `WARN message="Cannot capture request" empty="" error="open \"capture.jsonl\": permission denied" interval=1m0s mtls=true signal=logs stanza="splunk-connect-for-otlp://team a" tokens=2`+"\n", buf.String())
+	require.NotContains(t, enc.Fields, "tokens", "fields of a clone must not be added to the original encoder")
+}
+
+func TestCreateLoggerLevel(t *testing.T) {
+	logger, level, err := CreateLogger(LogSettings{Level: zapcore.WarnLevel, Format: "splunkd"})
+	require.NoError(t, err)
+	require.False(t, logger.Core().Enabled(zapcore.InfoLevel))
+	level.SetLevel(zapcore.DebugLevel)
+	require.True(t, logger.Core().Enabled(zapcore.DebugLevel))
+}
@@ -0,0 +1,22 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+// Config configures the HTTP server receiving Prometheus remote write requests.
+type Config struct {
+	confighttp.ServerConfig `mapstructure:",squash"`
+}
+
+func (c *Config) Validate() error {
+	if c.NetAddr.Endpoint == "" {
+		return errors.New("endpoint must be set")
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// This file decodes the prometheus.WriteRequest messages of remote write 1.0 and converts their samples to metrics.
+
+const (
+	nameLabel = "__name__"
+	// staleNaN is the value Prometheus writes to mark a series stale.
+	staleNaN = 0x7ff0000000000002
+)
+
+// metricType is the type of a metric family, as sent in the metadata of a write request.
+type metricType uint64
+
+const (
+	typeUnknown metricType = iota
+	typeCounter
+	typeGauge
+	typeHistogram
+	typeGaugeHistogram
+	typeSummary
+)
+
+type writeRequest struct {
+	timeseries []timeSeries
+	metadata   []metricMetadata
+}
+
+type timeSeries struct {
+	labels  []label
+	samples []sample
+}
+
+type label struct {
+	name  string
+	value string
+}
+
+type sample struct {
+	value float64
+	// timestamp is in milliseconds since the epoch.
+	timestamp int64
+}
+
+type metricMetadata struct {
+	typ        metricType
+	familyName string
+	help       string
+	unit       string
+}
+
+// field is a field of a protobuf message. Varint and fixed64 values are held by number, length-delimited values
+// by bytes.
+type field struct {
+	num    protowire.Number
+	typ    protowire.Type
+	number uint64
+	bytes  []byte
+}
+
+// decodeFields calls fn with each field of the protobuf message b.
+func decodeFields(b []byte, fn func(f field) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		f := field{num: num, typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			f.number, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed64Type:
+			f.number, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			f.bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeWriteRequest(b []byte) (writeRequest, error) {
+	var req writeRequest
+	err := decodeFields(b, func(f field) error {
+		switch {
+		case f.num == 1 && f.typ == protowire.BytesType:
+			ts, err := decodeTimeSeries(f.bytes)
+			if err != nil {
+				return err
+			}
+			req.timeseries = append(req.timeseries, ts)
+		case f.num == 3 && f.typ == protowire.BytesType:
+			md, err := decodeMetadata(f.bytes)
+			if err != nil {
+				return err
+			}
+			req.metadata = append(req.metadata, md)
+		}
+		return nil
+	})
+	return req, err
+}
+
+// decodeTimeSeries decodes the labels and the float samples of a series. Exemplars and native histograms are
+// ignored.
+func decodeTimeSeries(b []byte) (timeSeries, error) {
+	var ts timeSeries
+	err := decodeFields(b, func(f field) error {
+		switch {
+		case f.num == 1 && f.typ == protowire.BytesType:
+			var l label
+			err := decodeFields(f.bytes, func(f field) error {
+				switch {
+				case f.num == 1 && f.typ == protowire.BytesType:
+					l.name = string(f.bytes)
+				case f.num == 2 && f.typ == protowire.BytesType:
+					l.value = string(f.bytes)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			ts.labels = append(ts.labels, l)
+		case f.num == 2 && f.typ == protowire.BytesType:
+			var s sample
+			err := decodeFields(f.bytes, func(f field) error {
+				switch {
+				case f.num == 1 && f.typ == protowire.Fixed64Type:
+					s.value = math.Float64frombits(f.number)
+				case f.num == 2 && f.typ == protowire.VarintType:
+					s.timestamp = int64(f.number)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			ts.samples = append(ts.samples, s)
+		}
+		return nil
+	})
+	return ts, err
+}
+
+func decodeMetadata(b []byte) (metricMetadata, error) {
+	var md metricMetadata
+	err := decodeFields(b, func(f field) error {
+		switch {
+		case f.num == 1 && f.typ == protowire.VarintType:
+			md.typ = metricType(f.number)
+		case f.num == 2 && f.typ == protowire.BytesType:
+			md.familyName = string(f.bytes)
+		case f.num == 4 && f.typ == protowire.BytesType:
+			md.help = string(f.bytes)
+		case f.num == 5 && f.typ == protowire.BytesType:
+			md.unit = string(f.bytes)
+		}
+		return nil
+	})
+	return md, err
+}
+
+// toMetrics converts the samples of req to metrics named after the __name__ label of their series. The other
+// labels become attributes of the data points, so they are dimensions of the metric events like the attributes
+// of OTLP data points. Counters, and the buckets, counts and sums of histograms and summaries, are cumulative
+// sums; other series are gauges.
+func toMetrics(req writeRequest) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	families := make(map[string]metricMetadata, len(req.metadata))
+	for _, m := range req.metadata {
+		families[m.familyName] = m
+	}
+	byName := map[string]pmetric.Metric{}
+	for _, ts := range req.timeseries {
+		var name string
+		for _, l := range ts.labels {
+			if l.name == nameLabel {
+				name = l.value
+			}
+		}
+		if name == "" || len(ts.samples) == 0 {
+			continue
+		}
+		m, ok := byName[name]
+		if !ok {
+			m = metrics.AppendEmpty()
+			m.SetName(name)
+			family, cumulative := lookupFamily(families, name)
+			m.SetDescription(family.help)
+			m.SetUnit(family.unit)
+			if cumulative {
+				sum := m.SetEmptySum()
+				sum.SetIsMonotonic(true)
+				sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			} else {
+				m.SetEmptyGauge()
+			}
+			byName[name] = m
+		}
+		var points pmetric.NumberDataPointSlice
+		if m.Type() == pmetric.MetricTypeSum {
+			points = m.Sum().DataPoints()
+		} else {
+			points = m.Gauge().DataPoints()
+		}
+		for _, s := range ts.samples {
+			if math.Float64bits(s.value) == staleNaN {
+				continue
+			}
+			dp := points.AppendEmpty()
+			dp.SetDoubleValue(s.value)
+			dp.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(s.timestamp)))
+			for _, l := range ts.labels {
+				if l.name != nameLabel {
+					dp.Attributes().PutStr(l.name, l.value)
+				}
+			}
+		}
+	}
+	return md
+}
+
+// lookupFamily returns the metadata of the family of the series name, and whether its samples are cumulative.
+func lookupFamily(families map[string]metricMetadata, name string) (metricMetadata, bool) {
+	if family, ok := families[name]; ok {
+		return family, family.typ == typeCounter
+	}
+	for _, suffix := range []string{"_total", "_bucket", "_count", "_sum"} {
+		trimmed, found := strings.CutSuffix(name, suffix)
+		if !found {
+			continue
+		}
+		family, ok := families[trimmed]
+		if !ok {
+			continue
+		}
+		switch family.typ {
+		case typeCounter:
+			return family, true
+		case typeHistogram, typeSummary:
+			return family, suffix != "_total"
+		}
+		return family, false
+	}
+	return metricMetadata{}, false
+}
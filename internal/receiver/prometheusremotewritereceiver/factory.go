@@ -0,0 +1,38 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+// This file implements factory for the Prometheus remote write receiver.
+
+const (
+	typeStr        = "prometheusremotewrite"
+	stabilityLevel = component.StabilityLevelDevelopment
+)
+
+// NewFactory creates a factory for the Prometheus remote write receiver.
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		component.MustNewType(typeStr),
+		createDefaultConfig,
+		receiver.WithMetrics(createMetricsReceiver, stabilityLevel),
+	)
+}
+
+// createDefaultConfig creates the default configuration for the Prometheus remote write receiver.
+func createDefaultConfig() component.Config {
+	return &Config{ServerConfig: confighttp.NewDefaultServerConfig()}
+}
+
+func createMetricsReceiver(_ context.Context, set receiver.Settings, cfg component.Config, next consumer.Metrics) (receiver.Metrics, error) {
+	return newRemoteWriteReceiver(set, cfg.(*Config), next)
+}
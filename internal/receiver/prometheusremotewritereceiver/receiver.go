@@ -0,0 +1,126 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+)
+
+const (
+	// writePath is the path Prometheus and its agents post remote write requests to.
+	writePath = "/api/v1/write"
+	// dataFormat names the format of the received data in the metrics of the receiver.
+	dataFormat = "prometheus_remote_write"
+	// writeV2Proto is the protobuf message of remote write 2.0, which is not supported.
+	writeV2Proto = "io.prometheus.write.v2.Request"
+)
+
+var _ receiver.Metrics = &remoteWriteReceiver{}
+
+// remoteWriteReceiver receives the samples of Prometheus remote write 1.0 requests as metrics.
+type remoteWriteReceiver struct {
+	cfg        *Config
+	settings   receiver.Settings
+	next       consumer.Metrics
+	obsrecv    *receiverhelper.ObsReport
+	server     *http.Server
+	shutdownWG sync.WaitGroup
+}
+
+func newRemoteWriteReceiver(set receiver.Settings, cfg *Config, next consumer.Metrics) (*remoteWriteReceiver, error) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             set.ID,
+		Transport:              "http",
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &remoteWriteReceiver{cfg: cfg, settings: set, next: next, obsrecv: obsrecv}, nil
+}
+
+// Start listens for remote write requests in the background.
+func (r *remoteWriteReceiver) Start(ctx context.Context, host component.Host) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(writePath, r.handleWrite)
+	var err error
+	r.server, err = r.cfg.ToServer(ctx, host.GetExtensions(), r.settings.TelemetrySettings, mux)
+	if err != nil {
+		return err
+	}
+	l, err := r.cfg.ToListener(ctx)
+	if err != nil {
+		return err
+	}
+	r.settings.Logger.Info("Starting Prometheus remote write server", zap.String("endpoint", l.Addr().String()))
+	r.shutdownWG.Add(1)
+	go func() {
+		defer r.shutdownWG.Done()
+		if err := r.server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			componentstatus.ReportStatus(host, componentstatus.NewFatalErrorEvent(err))
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the server.
+func (r *remoteWriteReceiver) Shutdown(ctx context.Context) error {
+	if r.server == nil {
+		return nil
+	}
+	err := r.server.Shutdown(ctx)
+	r.shutdownWG.Wait()
+	return err
+}
+
+// handleWrite passes the samples of a write request to the next consumer. Prometheus retries requests failing with
+// a 5xx status, and drops requests failing with a 4xx status.
+func (r *remoteWriteReceiver) handleWrite(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "remote write requests must be posted", http.StatusMethodNotAllowed)
+		return
+	}
+	if strings.Contains(req.Header.Get("Content-Type"), writeV2Proto) {
+		http.Error(w, "remote write 2.0 is not supported, send remote write 1.0 requests", http.StatusUnsupportedMediaType)
+		return
+	}
+	// The body was decompressed by the server, according to its Content-Encoding.
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	wr, err := decodeWriteRequest(body)
+	if err != nil {
+		http.Error(w, "cannot decode the write request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	md := toMetrics(wr)
+
+	ctx := r.obsrecv.StartMetricsOp(req.Context())
+	err = r.next.ConsumeMetrics(ctx, md)
+	r.obsrecv.EndMetricsOp(ctx, dataFormat, md.DataPointCount(), err)
+	if err != nil {
+		status := http.StatusServiceUnavailable
+		if consumererror.IsPermanent(err) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,194 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// encodeWriteRequest encodes req as a prometheus.WriteRequest message.
+func encodeWriteRequest(req writeRequest) []byte {
+	var b []byte
+	for _, ts := range req.timeseries {
+		var tb []byte
+		for _, l := range ts.labels {
+			var lb []byte
+			lb = protowire.AppendTag(lb, 1, protowire.BytesType)
+			lb = protowire.AppendString(lb, l.name)
+			lb = protowire.AppendTag(lb, 2, protowire.BytesType)
+			lb = protowire.AppendString(lb, l.value)
+			tb = protowire.AppendTag(tb, 1, protowire.BytesType)
+			tb = protowire.AppendBytes(tb, lb)
+		}
+		for _, s := range ts.samples {
+			var sb []byte
+			sb = protowire.AppendTag(sb, 1, protowire.Fixed64Type)
+			sb = protowire.AppendFixed64(sb, math.Float64bits(s.value))
+			sb = protowire.AppendTag(sb, 2, protowire.VarintType)
+			sb = protowire.AppendVarint(sb, uint64(s.timestamp))
+			tb = protowire.AppendTag(tb, 2, protowire.BytesType)
+			tb = protowire.AppendBytes(tb, sb)
+		}
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, tb)
+	}
+	for _, md := range req.metadata {
+		var mb []byte
+		mb = protowire.AppendTag(mb, 1, protowire.VarintType)
+		mb = protowire.AppendVarint(mb, uint64(md.typ))
+		mb = protowire.AppendTag(mb, 2, protowire.BytesType)
+		mb = protowire.AppendString(mb, md.familyName)
+		mb = protowire.AppendTag(mb, 4, protowire.BytesType)
+		mb = protowire.AppendString(mb, md.help)
+		mb = protowire.AppendTag(mb, 5, protowire.BytesType)
+		mb = protowire.AppendString(mb, md.unit)
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, mb)
+	}
+	return b
+}
+
+func series(name string, value float64, labels ...string) timeSeries {
+	ts := timeSeries{
+		labels:  []label{{name: nameLabel, value: name}},
+		samples: []sample{{value: value, timestamp: 1700000000123}},
+	}
+	for i := 0; i+1 < len(labels); i += 2 {
+		ts.labels = append(ts.labels, label{name: labels[i], value: labels[i+1]})
+	}
+	return ts
+}
+
+func TestToMetrics(t *testing.T) {
+	req := writeRequest{
+		timeseries: []timeSeries{
+			series("http_requests_total", 10, "job", "api", "code", "200"),
+			series("http_requests_total", 2, "job", "api", "code", "500"),
+			series("temperature", 21.5, "room", "kitchen"),
+			series("latency_seconds_bucket", 3, "le", "0.5"),
+			series("latency_seconds_sum", 1.2),
+			series("stale", math.Float64frombits(staleNaN)),
+			{labels: []label{{name: "job", value: "nameless"}}, samples: []sample{{value: 1}}},
+		},
+		metadata: []metricMetadata{
+			{typ: typeCounter, familyName: "http_requests", help: "Requests served.", unit: "1"},
+			{typ: typeGauge, familyName: "temperature", help: "Room temperature.", unit: "Cel"},
+			{typ: typeHistogram, familyName: "latency_seconds", unit: "s"},
+		},
+	}
+	encoded := encodeWriteRequest(req)
+	decoded, err := decodeWriteRequest(encoded)
+	require.NoError(t, err)
+	// Compare the encodings, as the stale marker is not equal to itself.
+	assert.Equal(t, encoded, encodeWriteRequest(decoded))
+
+	md := toMetrics(decoded)
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 5, metrics.Len())
+
+	requests := metrics.At(0)
+	assert.Equal(t, "http_requests_total", requests.Name())
+	assert.Equal(t, "Requests served.", requests.Description())
+	require.Equal(t, pmetric.MetricTypeSum, requests.Type())
+	assert.True(t, requests.Sum().IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, requests.Sum().AggregationTemporality())
+	require.Equal(t, 2, requests.Sum().DataPoints().Len())
+	dp := requests.Sum().DataPoints().At(1)
+	assert.Equal(t, 2.0, dp.DoubleValue())
+	assert.Equal(t, time.UnixMilli(1700000000123).UTC(), dp.Timestamp().AsTime())
+	assert.Equal(t, map[string]any{"job": "api", "code": "500"}, dp.Attributes().AsRaw())
+
+	temperature := metrics.At(1)
+	assert.Equal(t, "Cel", temperature.Unit())
+	require.Equal(t, pmetric.MetricTypeGauge, temperature.Type())
+	assert.Equal(t, 21.5, temperature.Gauge().DataPoints().At(0).DoubleValue())
+
+	assert.Equal(t, pmetric.MetricTypeSum, metrics.At(2).Type())
+	assert.Equal(t, "s", metrics.At(2).Unit())
+	assert.Equal(t, pmetric.MetricTypeSum, metrics.At(3).Type())
+	assert.Equal(t, "stale", metrics.At(4).Name())
+	assert.Equal(t, 0, metrics.At(4).Gauge().DataPoints().Len())
+}
+
+func TestDecodeInvalidWriteRequest(t *testing.T) {
+	_, err := decodeWriteRequest([]byte{0x0a, 0x05, 0x01})
+	require.Error(t, err)
+}
+
+func TestReceiver(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	endpoint := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.NetAddr.Endpoint = endpoint
+	require.NoError(t, cfg.Validate())
+
+	var consumeErr error
+	sink := new(consumertest.MetricsSink)
+	next, err := consumer.NewMetrics(func(ctx context.Context, md pmetric.Metrics) error {
+		if consumeErr != nil {
+			return consumeErr
+		}
+		return sink.ConsumeMetrics(ctx, md)
+	})
+	require.NoError(t, err)
+	r, err := factory.CreateMetrics(t.Context(), receivertest.NewNopSettings(factory.Type()), cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(t.Context(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, r.Shutdown(t.Context()))
+	}()
+
+	url := fmt.Sprintf("http://%s%s", endpoint, writePath)
+	body := snappy.Encode(nil, encodeWriteRequest(writeRequest{timeseries: []timeSeries{series("up", 1, "job", "node")}}))
+	post := func(contentType string) int {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Content-Encoding", "snappy")
+		req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusNoContent, post("application/x-protobuf"))
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, "up", sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, post("application/x-protobuf;proto="+writeV2Proto))
+
+	consumeErr = errors.New("queue is full")
+	assert.Equal(t, http.StatusServiceUnavailable, post("application/x-protobuf"))
+	consumeErr = consumererror.NewPermanent(errors.New("invalid index"))
+	assert.Equal(t, http.StatusBadRequest, post("application/x-protobuf"))
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
@@ -0,0 +1,80 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"slices"
+)
+
+// SettingsChanges describes which components of a running input must be rebuilt to apply new settings.
+type SettingsChanges struct {
+	// Receiver is true when the listeners changed: their address, ports, TLS, the names of their tokens or whether
+	// the receivers which cannot check the tokens are allowed. The values of the tokens are secrets, compared by the
+	// input once read.
+	Receiver bool
+	// Exporters is true when the defaults or the destination of the events changed.
+	Exporters bool
+	// Restart lists the changed parameters that only apply when the input restarts.
+	Restart []string
+}
+
+// Changes compares the settings of a running input to next, the new settings of its stanza. It returns the
+// settings to apply, which keep the current value of the parameters that require a restart, and the components
+// to rebuild. The log level and the shutdown timeout apply without rebuilding any component.
+func (s Settings) Changes(next Settings) (Settings, SettingsChanges) {
+	var changes SettingsChanges
+	changes.Receiver = s.ListenAddress != next.ListenAddress ||
+		s.GRPCPort != next.GRPCPort ||
+		s.HTTPPort != next.HTTPPort ||
+		s.PrometheusRemoteWritePort != next.PrometheusRemoteWritePort ||
+		s.ZipkinPort != next.ZipkinPort ||
+		s.JaegerGRPCPort != next.JaegerGRPCPort ||
+		s.JaegerHTTPPort != next.JaegerHTTPPort ||
+		s.HECPort != next.HECPort ||
+		s.Syslog != next.Syslog ||
+		s.FluentForward != next.FluentForward ||
+		s.StatsD.Port != next.StatsD.Port ||
+		s.StatsD.AggregationInterval != next.StatsD.AggregationInterval ||
+		!slices.Equal(s.StatsD.Percentiles, next.StatsD.Percentiles) ||
+		s.GraphitePort != next.GraphitePort ||
+		s.TLS != next.TLS ||
+		!slices.Equal(s.AuthTokens, next.AuthTokens) ||
+		s.AllowUnauthenticated != next.AllowUnauthenticated
+	changes.Exporters = s.Index != next.Index ||
+		s.Source != next.Source ||
+		s.Host != next.Host ||
+		s.Logs != next.Logs ||
+		s.Traces != next.Traces ||
+		s.Metrics != next.Metrics ||
+		!slices.Equal(s.AllowedIndexes, next.AllowedIndexes) ||
+		s.IndexViolationAction != next.IndexViolationAction ||
+		s.OutputMode != next.OutputMode ||
+		s.HEC != next.HEC
+
+	restart := func(param string, changed bool) {
+		if changed {
+			changes.Restart = append(changes.Restart, param)
+		}
+	}
+	restart("health_port", s.HealthPort != next.HealthPort)
+	restart("telemetry_index", s.Telemetry.Index != next.Telemetry.Index)
+	restart("telemetry_interval", s.Telemetry.Interval != next.Telemetry.Interval)
+	restart("persistent_queue", s.PersistentQueue != next.PersistentQueue)
+	restart("capture", s.Capture.Enabled != next.Capture.Enabled)
+	restart("capture_max_size", s.Capture.MaxSize != next.Capture.MaxSize)
+	restart("capture_max_files", s.Capture.MaxFiles != next.Capture.MaxFiles)
+	restart("log_format", s.Log.Format != next.Log.Format)
+	restart("reload_interval", s.ReloadInterval != next.ReloadInterval)
+
+	applied := next
+	applied.Name = s.Name
+	applied.CheckpointDir = s.CheckpointDir
+	applied.HealthPort = s.HealthPort
+	applied.Telemetry = s.Telemetry
+	applied.PersistentQueue = s.PersistentQueue
+	applied.Capture = s.Capture
+	applied.Log.Format = s.Log.Format
+	applied.ReloadInterval = s.ReloadInterval
+	return applied, changes
+}
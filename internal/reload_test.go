@@ -0,0 +1,129 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSettingsChanges(t *testing.T) {
+	current, err := XMLStanza{Name: StanzaScheme + "otlp", Params: []XMLParam{
+		{Name: "index", Value: "otlp"},
+		{Name: "health_port", Value: "8080"},
+	}}.Settings()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		params  []XMLParam
+		changes SettingsChanges
+	}{
+		{
+			name:   "unchanged",
+			params: []XMLParam{{Name: "index", Value: "otlp"}, {Name: "health_port", Value: "8080"}},
+		},
+		{
+			name:    "port",
+			params:  []XMLParam{{Name: "index", Value: "otlp"}, {Name: "health_port", Value: "8080"}, {Name: "grpc_port", Value: "5317"}},
+			changes: SettingsChanges{Receiver: true},
+		},
+		{
+			name:    "statsd percentiles",
+			params:  []XMLParam{{Name: "index", Value: "otlp"}, {Name: "health_port", Value: "8080"}, {Name: "statsd_percentiles", Value: "99"}},
+			changes: SettingsChanges{Receiver: true},
+		},
+		{
+			name:    "unauthenticated receivers",
+			params:  []XMLParam{{Name: "index", Value: "otlp"}, {Name: "health_port", Value: "8080"}, {Name: "allow_unauthenticated_receivers", Value: "1"}},
+			changes: SettingsChanges{Receiver: true},
+		},
+		{
+			name:    "index",
+			params:  []XMLParam{{Name: "index", Value: "main"}, {Name: "health_port", Value: "8080"}},
+			changes: SettingsChanges{Exporters: true},
+		},
+		{
+			name:    "signal sourcetype",
+			params:  []XMLParam{{Name: "index", Value: "otlp"}, {Name: "health_port", Value: "8080"}, {Name: "logs_sourcetype", Value: "otel:logs"}},
+			changes: SettingsChanges{Exporters: true},
+		},
+		{
+			name:   "log level and shutdown timeout",
+			params: []XMLParam{{Name: "index", Value: "otlp"}, {Name: "health_port", Value: "8080"}, {Name: "log_level", Value: "debug"}, {Name: "shutdown_timeout", Value: "30"}},
+		},
+		{
+			name:    "restart",
+			params:  []XMLParam{{Name: "index", Value: "otlp"}, {Name: "telemetry_interval", Value: "30"}, {Name: "persistent_queue", Value: "1"}},
+			changes: SettingsChanges{Restart: []string{"health_port", "telemetry_interval", "persistent_queue"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := XMLStanza{Name: StanzaScheme + "otlp", Params: tt.params}.Settings()
+			require.NoError(t, err)
+			applied, changes := current.Changes(next)
+			require.Equal(t, tt.changes, changes)
+			// Parameters requiring a restart keep their current value.
+			require.Equal(t, current.HealthPort, applied.HealthPort)
+			require.Equal(t, current.Telemetry, applied.Telemetry)
+			require.Equal(t, current.PersistentQueue, applied.PersistentQueue)
+			require.Equal(t, next.Index, applied.Index)
+			require.Equal(t, next.GRPCPort, applied.GRPCPort)
+			require.Equal(t, next.Log.Level, applied.Log.Level)
+			require.Equal(t, next.ShutdownTimeout, applied.ShutdownTimeout)
+		})
+	}
+
+	next, err := XMLStanza{Name: StanzaScheme + "otlp", Params: []XMLParam{
+		{Name: "index", Value: "otlp"}, {Name: "health_port", Value: "8080"}, {Name: "log_level", Value: "debug"}, {Name: "shutdown_timeout", Value: "30"},
+	}}.Settings()
+	require.NoError(t, err)
+	applied, _ := current.Changes(next)
+	require.Equal(t, zapcore.DebugLevel, applied.Log.Level)
+	require.Equal(t, 30*time.Second, applied.ShutdownTimeout)
+}
+
+func TestFetchStanza(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Splunk key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/servicesNS/nobody/otlp_app/data/inputs/splunk-connect-for-otlp/otlp" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"entry":[{"name":"otlp","acl":{"app":"otlp_app"},"content":{
+			"disabled":false,"eai:acl":{"app":"otlp_app"},"eai:appName":"otlp_app","grpc_port":5317,
+			"index":"main","host":"$decideOnStartup","sourcetype":null}}]}`))
+	}))
+	defer server.Close()
+
+	input := XMLInput{ServerURI: server.URL, SessionKey: "key", Configuration: XMLConfig{Stanza: XMLStanza{
+		Name:   StanzaScheme + "otlp",
+		App:    "otlp_app",
+		Params: []XMLParam{{Name: "index", Value: "otlp"}},
+	}}}
+	fetched, err := input.FetchStanza(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, XMLStanza{Name: StanzaScheme + "otlp", App: "otlp_app", Params: []XMLParam{
+		{Name: "disabled", Value: "false"},
+		{Name: "grpc_port", Value: "5317"},
+		{Name: "host", Value: "$decideOnStartup"},
+		{Name: "index", Value: "main"},
+	}}, fetched.Configuration.Stanza)
+	require.Equal(t, server.URL, fetched.ServerURI)
+
+	input.Configuration.Stanza.Name = StanzaScheme + "missing"
+	_, err = input.FetchStanza(context.Background())
+	require.ErrorContains(t, err, `cannot read input "missing"`)
+}
@@ -8,30 +8,301 @@ const Scheme = `
 <scheme>
     <title>OTLP Input</title>
     <description>Receive data from OTLP</description>
-    <streaming_mode>simple</streaming_mode>
-    <use_single_instance>false</use_single_instance>
+    <streaming_mode>xml</streaming_mode>
+    <use_external_validation>true</use_external_validation>
+    <use_single_instance>true</use_single_instance>
     <endpoint>
         <args>
             <arg name="grpc_port">
                 <title>gRPC port</title>
                 <description>Port on which the receiver will listen for gRPC OTLP traffic</description>
-                <validation>is_avail_tcp_port('grpc_port')</validation>
                 <required_on_create>false</required_on_create>
             </arg>
 
             <arg name="http_port">
                 <title>HTTP Port</title>
                 <description>Port on which the receiver will listen for HTTP OTLP traffic</description>
-                <validation>is_avail_tcp_port('http_port')</validation>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="health_port">
+                <title>Health port</title>
+                <description>Port serving the liveness (/health/live) and readiness (/health/ready) of the input over HTTP. Disabled when empty</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="prometheus_remote_write_port">
+                <title>Prometheus remote write port</title>
+                <description>Port on which the receiver will listen for Prometheus remote write requests at /api/v1/write. Disabled when empty</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="zipkin_port">
+                <title>Zipkin port</title>
+                <description>Port on which the receiver will listen for Zipkin v1 and v2 spans in JSON or protobuf. Disabled when empty</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="jaeger_grpc_port">
+                <title>Jaeger gRPC port</title>
+                <description>Port on which the receiver will listen for Jaeger spans over gRPC. Disabled when empty</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="jaeger_http_port">
+                <title>Jaeger HTTP port</title>
+                <description>Port on which the receiver will listen for Jaeger spans in Thrift over HTTP at /api/traces. Disabled when empty</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="hec_port">
+                <title>HEC port</title>
+                <description>Port on which the receiver will listen for HTTP Event Collector events at /services/collector. Disabled when empty</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="syslog_tcp_port">
+                <title>Syslog TCP port</title>
+                <description>Port on which the receiver will listen for syslog messages over TCP. Disabled when empty</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="syslog_udp_port">
+                <title>Syslog UDP port</title>
+                <description>Port on which the receiver will listen for syslog messages over UDP. Disabled when empty</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="syslog_protocol">
+                <title>Syslog protocol</title>
+                <description>Format of the syslog messages: rfc5424 or rfc3164. Defaults to rfc5424</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="syslog_octet_counting">
+                <title>Syslog octet counting</title>
+                <description>Frame the syslog messages received over TCP with their length instead of newlines, as described in RFC 6587. Requires the rfc5424 protocol</description>
+                <data_type>boolean</data_type>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="fluentforward_port">
+                <title>Fluent Forward port</title>
+                <description>Port on which the receiver will listen for Fluent Forward records. Disabled when empty</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="fluentforward_tag">
+                <title>Fluent Forward tag</title>
+                <description>Metadata of the events the tag of Fluent Forward records is mapped to: source or sourcetype. Defaults to source</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="statsd_port">
+                <title>StatsD port</title>
+                <description>Port on which the receiver will listen for StatsD metrics over UDP. Disabled when empty</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="statsd_aggregation_interval">
+                <title>StatsD aggregation interval</title>
+                <description>Interval, in seconds, StatsD metrics are aggregated over. Defaults to 60</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="statsd_percentiles">
+                <title>StatsD percentiles</title>
+                <description>Comma-separated list of the percentiles of StatsD timings, histograms and distributions reported for each interval. Defaults to 50,90,95,99</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="graphite_port">
+                <title>Graphite port</title>
+                <description>Port on which the receiver will listen for Graphite metrics in the Carbon plaintext protocol over TCP. Disabled when empty</description>
                 <required_on_create>false</required_on_create>
             </arg>
 
             <arg name="listen_address">
                 <title>Listening address</title>
                 <description>The listening address to bind the receiver to</description>
-                <validation>
-                  validate(!match("listen_address", "^\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}$"), "Listening address is not valid")
-                </validation>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="logs_index">
+                <title>Logs index</title>
+                <description>Default index of logs lacking the com.splunk.index resource attribute. Defaults to the index of the input.</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="logs_sourcetype">
+                <title>Logs sourcetype</title>
+                <description>Default sourcetype of logs lacking the com.splunk.sourcetype resource attribute. Defaults to the sourcetype of the input</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="traces_index">
+                <title>Traces index</title>
+                <description>Default index of traces lacking the com.splunk.index resource attribute. Defaults to the index of the input.</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="traces_sourcetype">
+                <title>Traces sourcetype</title>
+                <description>Default sourcetype of traces lacking the com.splunk.sourcetype resource attribute. Defaults to the sourcetype of the input</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="metrics_index">
+                <title>Metrics index</title>
+                <description>Default index of metrics lacking the com.splunk.index resource attribute. Defaults to the index of the input. It must be a metrics index</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="metrics_sourcetype">
+                <title>Metrics sourcetype</title>
+                <description>Default sourcetype of metrics lacking the com.splunk.sourcetype resource attribute. Defaults to the sourcetype of the input</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="allowed_indexes">
+                <title>Allowed indexes</title>
+                <description>Comma-separated index names or regular expressions matching the indexes OTLP clients may route events to with the com.splunk.index attribute. Any index is allowed when empty</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="index_violation_action">
+                <title>Index violation action</title>
+                <description>What to do with events routed to an index that is not allowed: drop them, route them to the default index, or reject the OTLP request. Defaults to drop</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="cert_file">
+                <title>TLS certificate file</title>
+                <description>Path to the PEM certificate served by the OTLP listeners. Enables TLS when set. Relative paths are resolved against the app directory</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="key_file">
+                <title>TLS key file</title>
+                <description>Path to the PEM private key of the TLS certificate. Relative paths are resolved against the app directory</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="client_ca_file">
+                <title>TLS client CA file</title>
+                <description>Path to the PEM CA certificate used to verify client certificates. Requires clients to present a certificate (mTLS) when set</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="min_version">
+                <title>TLS minimum version</title>
+                <description>Minimum TLS version accepted by the OTLP listeners: 1.0, 1.1, 1.2 or 1.3. Defaults to 1.2</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="auth_tokens">
+                <title>Authentication tokens</title>
+                <description>Comma-separated names of secrets stored in Splunk under the splunk-connect-for-otlp realm. When set, OTLP requests must carry one of the secrets as an "Authorization: Bearer" header</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="allow_unauthenticated_receivers">
+                <title>Allow unauthenticated receivers</title>
+                <description>Accept, with authentication tokens, the receivers which cannot check the tokens, such as syslog, Fluent Forward, StatsD or Graphite. The input refuses to start otherwise</description>
+                <data_type>boolean</data_type>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="persistent_queue">
+                <title>Persistent queue</title>
+                <description>Buffer data in files under the checkpoint directory of the input instead of in memory, so it is delivered after splunkd restarts the input</description>
+                <data_type>boolean</data_type>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="output_mode">
+                <title>Output mode</title>
+                <description>Where events are sent: stdout writes them to splunkd, hec posts them to the HTTP Event Collector set by hec_endpoint. Defaults to stdout</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="hec_endpoint">
+                <title>HEC endpoint</title>
+                <description>URL of the HTTP Event Collector events are posted to in hec output mode, for example https://splunk:8088</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="hec_token">
+                <title>HEC token</title>
+                <description>Name of the secret, stored in Splunk under the splunk-connect-for-otlp realm, holding the HEC token</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="hec_use_ack">
+                <title>HEC acknowledgment</title>
+                <description>Wait for HEC to acknowledge events were indexed before dropping them from the queue. Requires indexer acknowledgment on the token</description>
+                <data_type>boolean</data_type>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="hec_ca_file">
+                <title>HEC CA file</title>
+                <description>Path to the PEM CA certificate used to verify the certificate of HEC. Relative paths are resolved against the app directory</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="telemetry_index">
+                <title>Telemetry index</title>
+                <description>Metrics index the input sends its own metrics to, such as accepted, refused and dropped items. Defaults to _metrics</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="telemetry_interval">
+                <title>Telemetry interval</title>
+                <description>Seconds between two exports of the metrics of the input. Defaults to 60. 0 disables them</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="shutdown_timeout">
+                <title>Shutdown timeout</title>
+                <description>Seconds the input waits for buffered data to be written when it stops, before dropping it. Defaults to 10</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="reload_interval">
+                <title>Reload interval</title>
+                <description>Seconds between two reads of the stanza from the Splunk REST API, to apply its changes without restarting the input. Defaults to 0, which disables polling</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="log_level">
+                <title>Log level</title>
+                <description>Minimum level of the logs the input writes to splunkd.log: debug, info, warn or error. Defaults to info</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="log_format">
+                <title>Log format</title>
+                <description>Format of the logs the input writes to splunkd.log: json, or splunkd for key=value lines starting with their level. Defaults to json</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="capture">
+                <title>Capture</title>
+                <description>Record every received request to capture files under the checkpoint directory of the input, so it can be replayed with the replay command</description>
+                <data_type>boolean</data_type>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="capture_max_size">
+                <title>Capture maximum size</title>
+                <description>Size in megabytes the capture file is rotated at. Defaults to 100</description>
+                <required_on_create>false</required_on_create>
+            </arg>
+
+            <arg name="capture_max_files">
+                <title>Capture maximum files</title>
+                <description>Number of rotated capture files kept. Defaults to 10</description>
                 <required_on_create>false</required_on_create>
             </arg>
 
@@ -0,0 +1,208 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PasswordRealm is the realm of the secrets this input stores in Splunk.
+const PasswordRealm = "splunk-connect-for-otlp"
+
+// SplunkdClient calls the Splunk management API with the session key handed to the input by splunkd.
+type SplunkdClient struct {
+	httpClient *http.Client
+	serverURI  string
+	sessionKey string
+}
+
+// NewSplunkdClient returns a client for the management API at serverURI.
+func NewSplunkdClient(serverURI, sessionKey string) (*SplunkdClient, error) {
+	if serverURI == "" || sessionKey == "" {
+		return nil, errors.New("server_uri and session_key are required to call the Splunk management API")
+	}
+	u, err := url.Parse(serverURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server_uri %q: %w", serverURI, err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if ip := net.ParseIP(u.Hostname()); u.Hostname() == "localhost" || (ip != nil && ip.IsLoopback()) {
+		// splunkd serves its management port with a self-signed certificate by default.
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // loopback only
+	}
+	return &SplunkdClient{
+		httpClient: &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		serverURI:  serverURI,
+		sessionKey: sessionKey,
+	}, nil
+}
+
+// Password returns the clear text of the secret stored under PasswordRealm with the given name in app.
+func (c *SplunkdClient) Password(ctx context.Context, app, name string) (string, error) {
+	path := fmt.Sprintf("/servicesNS/nobody/%s/storage/passwords/%s", url.PathEscape(app), url.PathEscape(PasswordRealm+":"+name+":"))
+	var response struct {
+		Entry []struct {
+			Content struct {
+				ClearPassword string `json:"clear_password"`
+			} `json:"content"`
+		} `json:"entry"`
+	}
+	if err := c.get(ctx, path, &response); err != nil {
+		return "", fmt.Errorf("cannot read secret %q: %w", name, err)
+	}
+	if len(response.Entry) == 0 {
+		return "", fmt.Errorf("secret %q not found", name)
+	}
+	return response.Entry[0].Content.ClearPassword, nil
+}
+
+// Stanza returns the current parameters of the input stanza named name, as configured in app.
+func (c *SplunkdClient) Stanza(ctx context.Context, app, name string) (XMLStanza, error) {
+	inputName := strings.TrimPrefix(name, StanzaScheme)
+	path := fmt.Sprintf("/servicesNS/nobody/%s/data/inputs/%s/%s", url.PathEscape(app),
+		strings.TrimSuffix(StanzaScheme, "://"), url.PathEscape(inputName))
+	var response struct {
+		Entry []struct {
+			ACL struct {
+				App string `json:"app"`
+			} `json:"acl"`
+			Content map[string]any `json:"content"`
+		} `json:"entry"`
+	}
+	if err := c.get(ctx, path, &response); err != nil {
+		return XMLStanza{}, fmt.Errorf("cannot read input %q: %w", inputName, err)
+	}
+	if len(response.Entry) == 0 {
+		return XMLStanza{}, fmt.Errorf("input %q not found", inputName)
+	}
+	entry := response.Entry[0]
+	stanza := XMLStanza{Name: name, App: entry.ACL.App}
+	for param, value := range entry.Content {
+		// Unset parameters are null, and the eai: fields describe the entity rather than the input.
+		if value == nil || strings.HasPrefix(param, "eai:") {
+			continue
+		}
+		stanza.Params = append(stanza.Params, XMLParam{Name: param, Value: paramValue(value)})
+	}
+	sort.Slice(stanza.Params, func(i, j int) bool {
+		return stanza.Params[i].Name < stanza.Params[j].Name
+	})
+	return stanza, nil
+}
+
+// paramValue returns the value of a parameter as written in a stanza. Lists are joined with commas.
+func paramValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, paramValue(item))
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (c *SplunkdClient) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.serverURI+path+"?output_mode=json", http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Splunk "+c.sessionKey)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, body)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// FetchSecrets reads the secrets named names from the passwords stored in Splunk, such as the bearer tokens
+// accepted by the listeners or the HEC token. Inputs defined in a configuration file read them from Secrets.
+func (x XMLInput) FetchSecrets(ctx context.Context, app string, names []string) ([]string, error) {
+	if x.Secrets != nil {
+		return x.lookupSecrets(names)
+	}
+	client, err := NewSplunkdClient(x.ServerURI, x.SessionKey)
+	if err != nil {
+		return nil, err
+	}
+	if app == "" {
+		app = "-"
+	}
+	secrets := make([]string, 0, len(names))
+	var errs []error
+	for _, name := range names {
+		secret, err := client.Password(ctx, app, name)
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+		case secret == "":
+			errs = append(errs, fmt.Errorf("secret %q is empty", name))
+		default:
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets, errors.Join(errs...)
+}
+
+// FetchStanza returns the input with the current parameters of its stanza, read from Splunk, so changes made
+// after splunkd started the input can be applied.
+func (x XMLInput) FetchStanza(ctx context.Context) (XMLInput, error) {
+	client, err := NewSplunkdClient(x.ServerURI, x.SessionKey)
+	if err != nil {
+		return x, err
+	}
+	app := x.Configuration.Stanza.App
+	if app == "" {
+		app = "-"
+	}
+	stanza, err := client.Stanza(ctx, app, x.Configuration.Stanza.Name)
+	if err != nil {
+		return x, err
+	}
+	if stanza.App == "" {
+		stanza.App = x.Configuration.Stanza.App
+	}
+	x.Configuration = XMLConfig{Stanza: stanza, Stanzas: []XMLStanza{stanza}}
+	return x, nil
+}
+
+// lookupSecrets returns the secrets named names from the secrets of the configuration file.
+func (x XMLInput) lookupSecrets(names []string) ([]string, error) {
+	secrets := make([]string, 0, len(names))
+	var errs []error
+	for _, name := range names {
+		secret, ok := x.Secrets[name]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("secret %q not found", name))
+		case secret == "":
+			errs = append(errs, fmt.Errorf("secret %q is empty", name))
+		default:
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets, errors.Join(errs...)
+}
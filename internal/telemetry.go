@@ -0,0 +1,224 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+var _ component.Component = &SelfTelemetry{}
+
+// SelfTelemetry collects the metrics the receivers and exporters of the input record about themselves,
+// such as accepted and refused items or send failures, and periodically exports them as Splunk metric events.
+type SelfTelemetry struct {
+	logger   *zap.Logger
+	reader   *sdkmetric.ManualReader
+	provider *sdkmetric.MeterProvider
+	resource pcommon.Resource
+	interval time.Duration
+	next     consumer.Metrics
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewSelfTelemetry returns the self telemetry of the input. Metrics recorded through MeterProvider are sent
+// to next every interval, with the attributes of resource. They are also collected by readers.
+func NewSelfTelemetry(logger *zap.Logger, interval time.Duration, resource pcommon.Resource, next consumer.Metrics, readers ...sdkmetric.Reader) *SelfTelemetry {
+	reader := sdkmetric.NewManualReader(sdkmetric.WithTemporalitySelector(deltaTemporality))
+	return &SelfTelemetry{
+		logger:   logger,
+		reader:   reader,
+		provider: NewMeterProvider(append(readers, reader)...),
+		resource: resource,
+		interval: interval,
+		next:     next,
+		stop:     make(chan struct{}),
+	}
+}
+
+// NewMeterProvider returns a meter provider collecting the metrics of the components of the input with readers.
+func NewMeterProvider(readers ...sdkmetric.Reader) *sdkmetric.MeterProvider {
+	opts := []sdkmetric.Option{sdkmetric.WithView(dropInstrumentation)}
+	for _, reader := range readers {
+		opts = append(opts, sdkmetric.WithReader(reader))
+	}
+	return sdkmetric.NewMeterProvider(opts...)
+}
+
+// instrumentationScopePrefix prefixes the scope of the gRPC and HTTP server instrumentation of the receivers.
+const instrumentationScopePrefix = "go.opentelemetry.io/contrib/instrumentation/"
+
+// dropInstrumentation drops the per-request histograms of the gRPC and HTTP servers, which are too verbose
+// to send to Splunk. The receiver metrics already count accepted and refused items.
+func dropInstrumentation(i sdkmetric.Instrument) (sdkmetric.Stream, bool) {
+	if strings.HasPrefix(i.Scope.Name, instrumentationScopePrefix) {
+		return sdkmetric.Stream{Aggregation: sdkmetric.AggregationDrop{}}, true
+	}
+	return sdkmetric.Stream{}, false
+}
+
+// deltaTemporality exports counters and histograms as their change since the previous export, so summing their
+// metric events over a time span in Splunk gives the change over the span. Up-down counters, which are levels
+// rather than counts, stay cumulative.
+func deltaTemporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case sdkmetric.InstrumentKindUpDownCounter, sdkmetric.InstrumentKindObservableUpDownCounter:
+		return metricdata.CumulativeTemporality
+	}
+	return metricdata.DeltaTemporality
+}
+
+// MeterProvider returns the meter provider components must record their metrics with.
+func (t *SelfTelemetry) MeterProvider() metric.MeterProvider {
+	return t.provider
+}
+
+// Start exports the collected metrics every interval in the background.
+func (t *SelfTelemetry) Start(ctx context.Context, _ component.Host) error {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := t.Export(ctx); err != nil {
+					t.logger.Warn("Cannot export self telemetry", zap.Error(err))
+				}
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown exports the metrics collected since the last export and stops the meter provider.
+func (t *SelfTelemetry) Shutdown(ctx context.Context) error {
+	close(t.stop)
+	t.wg.Wait()
+	return errors.Join(t.Export(ctx), t.provider.Shutdown(ctx))
+}
+
+// Export collects the current value of every metric and sends it to the next consumer.
+func (t *SelfTelemetry) Export(ctx context.Context) error {
+	var rm metricdata.ResourceMetrics
+	if err := t.reader.Collect(ctx, &rm); err != nil {
+		return err
+	}
+	md := t.convert(rm)
+	if md.DataPointCount() == 0 {
+		return nil
+	}
+	return t.next.ConsumeMetrics(ctx, md)
+}
+
+// convert translates the metrics collected by the SDK into pdata.
+func (t *SelfTelemetry) convert(rm metricdata.ResourceMetrics) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	resourceMetrics := md.ResourceMetrics().AppendEmpty()
+	t.resource.CopyTo(resourceMetrics.Resource())
+	for _, sm := range rm.ScopeMetrics {
+		scopeMetrics := resourceMetrics.ScopeMetrics().AppendEmpty()
+		scopeMetrics.Scope().SetName(sm.Scope.Name)
+		scopeMetrics.Scope().SetVersion(sm.Scope.Version)
+		for _, m := range sm.Metrics {
+			dest := pmetric.NewMetric()
+			dest.SetName(m.Name)
+			dest.SetDescription(m.Description)
+			dest.SetUnit(m.Unit)
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				copyIntPoints(dest.SetEmptyGauge().DataPoints(), data.DataPoints)
+			case metricdata.Gauge[float64]:
+				copyDoublePoints(dest.SetEmptyGauge().DataPoints(), data.DataPoints)
+			case metricdata.Sum[int64]:
+				sum := dest.SetEmptySum()
+				sum.SetIsMonotonic(data.IsMonotonic)
+				sum.SetAggregationTemporality(temporality(data.Temporality))
+				copyIntPoints(sum.DataPoints(), data.DataPoints)
+			case metricdata.Sum[float64]:
+				sum := dest.SetEmptySum()
+				sum.SetIsMonotonic(data.IsMonotonic)
+				sum.SetAggregationTemporality(temporality(data.Temporality))
+				copyDoublePoints(sum.DataPoints(), data.DataPoints)
+			case metricdata.Histogram[int64]:
+				copyHistogram(dest, data)
+			case metricdata.Histogram[float64]:
+				copyHistogram(dest, data)
+			default:
+				t.logger.Debug("Ignoring self telemetry metric of unsupported type", zap.String("metric", m.Name))
+				continue
+			}
+			dest.MoveTo(scopeMetrics.Metrics().AppendEmpty())
+		}
+	}
+	return md
+}
+
+func temporality(t metricdata.Temporality) pmetric.AggregationTemporality {
+	if t == metricdata.DeltaTemporality {
+		return pmetric.AggregationTemporalityDelta
+	}
+	return pmetric.AggregationTemporalityCumulative
+}
+
+func copyIntPoints(dest pmetric.NumberDataPointSlice, points []metricdata.DataPoint[int64]) {
+	for _, p := range points {
+		dp := dest.AppendEmpty()
+		copyPointMetadata(dp.Attributes(), p.Attributes, p.StartTime, p.Time, dp.SetStartTimestamp, dp.SetTimestamp)
+		dp.SetIntValue(p.Value)
+	}
+}
+
+func copyDoublePoints(dest pmetric.NumberDataPointSlice, points []metricdata.DataPoint[float64]) {
+	for _, p := range points {
+		dp := dest.AppendEmpty()
+		copyPointMetadata(dp.Attributes(), p.Attributes, p.StartTime, p.Time, dp.SetStartTimestamp, dp.SetTimestamp)
+		dp.SetDoubleValue(p.Value)
+	}
+}
+
+func copyHistogram[N int64 | float64](dest pmetric.Metric, data metricdata.Histogram[N]) {
+	histogram := dest.SetEmptyHistogram()
+	histogram.SetAggregationTemporality(temporality(data.Temporality))
+	for _, p := range data.DataPoints {
+		dp := histogram.DataPoints().AppendEmpty()
+		copyPointMetadata(dp.Attributes(), p.Attributes, p.StartTime, p.Time, dp.SetStartTimestamp, dp.SetTimestamp)
+		dp.SetCount(p.Count)
+		dp.SetSum(float64(p.Sum))
+		if v, ok := p.Min.Value(); ok {
+			dp.SetMin(float64(v))
+		}
+		if v, ok := p.Max.Value(); ok {
+			dp.SetMax(float64(v))
+		}
+		dp.ExplicitBounds().FromRaw(p.Bounds)
+		dp.BucketCounts().FromRaw(p.BucketCounts)
+	}
+}
+
+func copyPointMetadata(dest pcommon.Map, attrs attribute.Set, start, ts time.Time, setStart, setTime func(pcommon.Timestamp)) {
+	for _, kv := range attrs.ToSlice() {
+		dest.PutStr(string(kv.Key), kv.Value.Emit())
+	}
+	setStart(pcommon.NewTimestampFromTime(start))
+	setTime(pcommon.NewTimestampFromTime(ts))
+}
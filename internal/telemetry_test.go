@@ -0,0 +1,73 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+func TestSelfTelemetryExport(t *testing.T) {
+	sink := &consumertest.MetricsSink{}
+	res := pcommon.NewResource()
+	res.Attributes().PutStr("service.name", "splunk-connect-for-otlp")
+	telemetry := NewSelfTelemetry(zap.NewNop(), time.Hour, res, sink)
+
+	meter := telemetry.MeterProvider().Meter("go.opentelemetry.io/collector/receiver/otlpreceiver")
+	accepted, err := meter.Int64Counter("otelcol_receiver_accepted_log_records")
+	require.NoError(t, err)
+	queueSize, err := meter.Int64Gauge("otelcol_exporter_queue_size")
+	require.NoError(t, err)
+	instrumentation, err := telemetry.MeterProvider().Meter(instrumentationScopePrefix + "net/http/otelhttp").Int64Counter("http.server.requests")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	accepted.Add(ctx, 3, metric.WithAttributes(attribute.String("transport", "http")))
+	queueSize.Record(ctx, 7)
+	instrumentation.Add(ctx, 1)
+
+	require.NoError(t, telemetry.Export(ctx))
+	accepted.Add(ctx, 2, metric.WithAttributes(attribute.String("transport", "http")))
+	require.NoError(t, telemetry.Start(ctx, nil))
+	require.NoError(t, telemetry.Shutdown(ctx))
+
+	require.Len(t, sink.AllMetrics(), 2)
+	md := sink.AllMetrics()[0]
+	require.Equal(t, 2, md.MetricCount(), "instrumentation metrics must be dropped")
+	rm := md.ResourceMetrics().At(0)
+	require.Equal(t, res.Attributes().AsRaw(), rm.Resource().Attributes().AsRaw())
+
+	metrics := map[string]pmetric.Metric{}
+	for i := 0; i < rm.ScopeMetrics().Len(); i++ {
+		for j := 0; j < rm.ScopeMetrics().At(i).Metrics().Len(); j++ {
+			m := rm.ScopeMetrics().At(i).Metrics().At(j)
+			metrics[m.Name()] = m
+		}
+	}
+	sum := metrics["otelcol_receiver_accepted_log_records"].Sum()
+	require.True(t, sum.IsMonotonic())
+	require.Equal(t, pmetric.AggregationTemporalityDelta, sum.AggregationTemporality())
+	require.Equal(t, int64(3), sum.DataPoints().At(0).IntValue())
+	require.Equal(t, map[string]any{"transport": "http"}, sum.DataPoints().At(0).Attributes().AsRaw())
+	require.Equal(t, int64(7), metrics["otelcol_exporter_queue_size"].Gauge().DataPoints().At(0).IntValue())
+
+	// Counters are exported as their change since the previous export.
+	next := sink.AllMetrics()[1].ResourceMetrics().At(0).ScopeMetrics()
+	for i := 0; i < next.Len(); i++ {
+		for j := 0; j < next.At(i).Metrics().Len(); j++ {
+			if m := next.At(i).Metrics().At(j); m.Name() == "otelcol_receiver_accepted_log_records" {
+				require.Equal(t, int64(2), m.Sum().DataPoints().At(0).IntValue())
+			}
+		}
+	}
+}
@@ -6,6 +6,8 @@ package testutils
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"net"
@@ -271,6 +273,70 @@ func CollectLines(t *testing.T, ch <-chan string, expectedCount int) []string {
 	return lines
 }
 
+// CollectEvents reads expectedCount events written in Splunk XML streaming mode from the provided channel or fails
+// on timeout, and returns them as lines of HEC JSON.
+func CollectEvents(t *testing.T, ch <-chan string, expectedCount int) []string {
+	t.Helper()
+
+	lines := CollectLines(t, ch, expectedCount)
+	events := make([]string, 0, len(lines))
+	for _, line := range lines {
+		events = append(events, HECEventFromXML(t, line))
+	}
+	return events
+}
+
+// HECEventFromXML returns the event of a Splunk XML streaming document as a line of HEC JSON, with the fields in
+// the order the stdout exporter writes them.
+func HECEventFromXML(t *testing.T, line string) string {
+	t.Helper()
+
+	var stream struct {
+		Event struct {
+			Time       string `xml:"time"`
+			Index      string `xml:"index"`
+			SourceType string `xml:"sourcetype"`
+			Host       string `xml:"host"`
+			Source     string `xml:"source"`
+			Data       string `xml:"data"`
+		} `xml:"event"`
+	}
+	if err := xml.Unmarshal([]byte(line), &stream); err != nil {
+		t.Fatalf("failed to read XML streaming event %q: %v", line, err)
+	}
+	var data struct {
+		Event  json.RawMessage `json:"event"`
+		Fields json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(stream.Event.Data), &data); err != nil {
+		t.Fatalf("failed to read the data of XML streaming event %q: %v", line, err)
+	}
+	event := struct {
+		Event      json.RawMessage `json:"event"`
+		Fields     json.RawMessage `json:"fields,omitempty"`
+		Host       string          `json:"host"`
+		Source     string          `json:"source,omitempty"`
+		SourceType string          `json:"sourcetype,omitempty"`
+		Index      string          `json:"index,omitempty"`
+		Time       json.RawMessage `json:"time,omitempty"`
+	}{
+		Event:      data.Event,
+		Fields:     data.Fields,
+		Host:       stream.Event.Host,
+		Source:     stream.Event.Source,
+		SourceType: stream.Event.SourceType,
+		Index:      stream.Event.Index,
+	}
+	if stream.Event.Time != "" {
+		event.Time = json.RawMessage(stream.Event.Time)
+	}
+	b, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("failed to write XML streaming event %q as HEC JSON: %v", line, err)
+	}
+	return string(b)
+}
+
 func LoadExpectedHecData(t *testing.T, path string) []byte {
 	t.Helper()
 
@@ -0,0 +1,142 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+type xmlValidationError struct {
+	XMLName xml.Name `xml:"error"`
+	Message string   `xml:"message"`
+}
+
+// Validate checks the parameters of the stanza Splunk asks to validate, reporting every problem found.
+func (x XMLInput) Validate() error {
+	settings, err := x.Item.Settings()
+	if err != nil {
+		return err
+	}
+	settings.CheckpointDir = x.CheckpointDir
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	var errs []error
+	held := x.heldListeners(ctx)
+	for _, l := range settings.Listeners() {
+		// The running input holds the ports of the stanza being edited.
+		if held[Listener{Network: l.Network, Port: l.Port}] {
+			continue
+		}
+		if err = checkBindable(settings.ListenAddress, l); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, checkTLSFiles(settings.TLS)...)
+	if err = settings.checkCheckpointDir(); err != nil {
+		errs = append(errs, err)
+	}
+	if err = checkPEMFile("hec_ca_file", settings.HEC.CAFile); err != nil {
+		errs = append(errs, err)
+	}
+	secrets := settings.AuthTokens
+	if settings.OutputMode == "hec" {
+		secrets = append(secrets, settings.HEC.Token)
+	}
+	if len(secrets) > 0 {
+		if _, err = x.FetchSecrets(ctx, "", secrets); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// heldListeners returns the listeners of the stanza Splunk asks to validate as the stanza is saved, keyed by
+// network and port. It returns nil when the stanza is new, or cannot be read from Splunk.
+func (x XMLInput) heldListeners(ctx context.Context) map[Listener]bool {
+	client, err := NewSplunkdClient(x.ServerURI, x.SessionKey)
+	if err != nil {
+		return nil
+	}
+	app := x.Item.App
+	if app == "" {
+		app = "-"
+	}
+	stanza, err := client.Stanza(ctx, app, x.Item.Name)
+	if err != nil {
+		return nil
+	}
+	settings, err := stanza.Settings()
+	if err != nil {
+		return nil
+	}
+	held := make(map[Listener]bool)
+	for _, l := range settings.Listeners() {
+		held[Listener{Network: l.Network, Port: l.Port}] = true
+	}
+	return held
+}
+
+func checkTLSFiles(t TLSSettings) []error {
+	if !t.Enabled() {
+		return nil
+	}
+	var errs []error
+	if _, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile); err != nil {
+		errs = append(errs, fmt.Errorf("cannot load certificate %s and key %s: %w", t.CertFile, t.KeyFile, err))
+	}
+	if err := checkPEMFile("client_ca_file", t.ClientCAFile); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// checkPEMFile reports an error if the file set by param cannot be read or holds no PEM certificate.
+func checkPEMFile(param, path string) error {
+	if path == "" {
+		return nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", param, err)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(b) {
+		return fmt.Errorf("%s %s does not contain any PEM certificate", param, path)
+	}
+	return nil
+}
+
+func checkBindable(address string, l Listener) error {
+	endpoint := net.JoinHostPort(address, strconv.Itoa(l.Port))
+	var c io.Closer
+	var err error
+	if l.Network == "udp" {
+		c, err = net.ListenPacket("udp", endpoint)
+	} else {
+		c, err = net.Listen("tcp", endpoint)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot listen on %s: %w", endpoint, err)
+	}
+	return c.Close()
+}
+
+// FormatValidationError renders err as the document Splunk expects on stdout when argument validation fails.
+func FormatValidationError(err error) string {
+	b, marshalErr := xml.Marshal(xmlValidationError{Message: err.Error()})
+	if marshalErr != nil {
+		return "<error><message>invalid configuration</message></error>"
+	}
+	return string(b)
+}
@@ -0,0 +1,147 @@
+// Copyright Splunk Inc. 2025
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/splunk/otlp2splunk/internal/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func validationInput(t *testing.T, params string) XMLInput {
+	t.Helper()
+	input := fmt.Sprintf(`
+<?xml version="1.0" encoding="UTF-8"?>
+<items>
+  <server_host>773c28971b2a</server_host>
+  <server_uri>https://127.0.0.1:8089</server_uri>
+  <session_key>OwLHq7jpfgz0WLe5t8KwZuxT4QZRggryMB2io6Phimb2zi5ErifFvx0Eu8WTmfviO</session_key>
+  <checkpoint_dir>/opt/splunk/var/lib/splunk/modinputs/splunk-connect-for-otlp</checkpoint_dir>
+  <item name="specialmind">
+    %s
+  </item>
+</items>`, params)
+
+	var config XMLInput
+	require.NoError(t, xml.Unmarshal([]byte(input), &config))
+	return config
+}
+
+func TestValidate(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		_ = busy.Close()
+	}()
+	busyPort := busy.Addr().(*net.TCPAddr).Port
+
+	tests := []struct {
+		name     string
+		params   string
+		expected []string
+	}{
+		{
+			name: "ephemeral ports",
+			params: `<param name="grpc_port">0</param>
+<param name="http_port">0</param>
+<param name="listen_address">127.0.0.1</param>`,
+			expected: []string{
+				"grpc_port must be set to a port between 1 and 65535",
+				"http_port must be set to a port between 1 and 65535",
+			},
+		},
+		{
+			name: "not a number",
+			params: `<param name="grpc_port">abc</param>
+<param name="http_port">70000</param>
+<param name="listen_address">localhost:1</param>`,
+			expected: []string{
+				`grpc_port "abc" is not a number`,
+				"http_port 70000 is out of range, it must be between 0 and 65535",
+				`listen_address "localhost:1" is not a valid IP address`,
+			},
+		},
+		{
+			name: "same ports",
+			params: `<param name="grpc_port">4317</param>
+<param name="http_port">4317</param>
+<param name="listen_address">127.0.0.1</param>`,
+			expected: []string{"grpc_port and http_port must be different, both are set to 4317"},
+		},
+		{
+			name: "health port collision",
+			params: `<param name="grpc_port">4317</param>
+<param name="http_port">4318</param>
+<param name="health_port">4318</param>
+<param name="listen_address">127.0.0.1</param>`,
+			expected: []string{"http_port and health_port must be different, both are set to 4318"},
+		},
+		{
+			name: "port in use",
+			params: fmt.Sprintf(`<param name="grpc_port">%d</param>
+<param name="http_port">4318</param>
+<param name="listen_address">127.0.0.1</param>`, busyPort),
+			expected: []string{fmt.Sprintf("cannot listen on 127.0.0.1:%d", busyPort)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validationInput(t, tt.params).Validate()
+			require.Error(t, err)
+			for _, expected := range tt.expected {
+				require.ErrorContains(t, err, expected)
+			}
+		})
+	}
+}
+
+func TestValidateAcceptsFreePorts(t *testing.T) {
+	config := validationInput(t, fmt.Sprintf(`<param name="grpc_port">%d</param>
+<param name="http_port">%d</param>
+<param name="listen_address">127.0.0.1</param>`, testutils.GetFreePort(t), testutils.GetFreePort(t)))
+	require.NoError(t, config.Validate())
+}
+
+func TestValidateSkipsPortsOfEditedStanza(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		_ = busy.Close()
+	}()
+	busyPort := busy.Addr().(*net.TCPAddr).Port
+
+	// The running input holds the gRPC port of the stanza as it is saved.
+	splunkd := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servicesNS/nobody/-/data/inputs/splunk-connect-for-otlp/specialmind" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"entry":[{"acl":{"app":"search"},"content":{"grpc_port":"%d","http_port":"4318"}}]}`, busyPort)
+	}))
+	defer splunkd.Close()
+
+	params := fmt.Sprintf(`<param name="grpc_port">%d</param>
+<param name="http_port">%d</param>
+<param name="listen_address">127.0.0.1</param>`, busyPort, testutils.GetFreePort(t))
+	config := validationInput(t, params)
+	config.ServerURI = splunkd.URL
+	require.NoError(t, config.Validate())
+
+	// A stanza which is not saved yet holds no port.
+	config.Item.Name = "new"
+	require.ErrorContains(t, config.Validate(), fmt.Sprintf("cannot listen on 127.0.0.1:%d", busyPort))
+}
+
+func TestFormatValidationError(t *testing.T) {
+	err := errors.New(`http_port "<4318>" is not a number`)
+	require.Equal(t, `<error><message>http_port &#34;&lt;4318&gt;&#34; is not a number</message></error>`, FormatValidationError(err))
+}
@@ -3,3 +3,51 @@ interval = 0
 grpc_port = <4317>
 http_port = <4318>
 listen_address = <0.0.0.0>
+health_port = <port>
+prometheus_remote_write_port = <port>
+zipkin_port = <port>
+jaeger_grpc_port = <port>
+jaeger_http_port = <port>
+hec_port = <port>
+syslog_tcp_port = <port>
+syslog_udp_port = <port>
+syslog_protocol = <rfc5424|rfc3164>
+syslog_octet_counting = <bool>
+fluentforward_port = <port>
+fluentforward_tag = <source|sourcetype>
+statsd_port = <port>
+statsd_aggregation_interval = <seconds>
+statsd_percentiles = <comma-separated list of percentiles>
+graphite_port = <port>
+# The *_sourcetype settings are the sourcetypes of events lacking the com.splunk.sourcetype resource attribute.
+# The sourcetype of the stanza is the sourcetype splunkd reads the output of the input with, so it is not used for events.
+logs_index = <string>
+logs_sourcetype = <string>
+traces_index = <string>
+traces_sourcetype = <string>
+metrics_index = <string>
+metrics_sourcetype = <string>
+allowed_indexes = <comma-separated list of index names or regular expressions>
+index_violation_action = <drop|default|reject>
+cert_file = <string>
+key_file = <string>
+client_ca_file = <string>
+min_version = <1.0|1.1|1.2|1.3>
+auth_tokens = <comma-separated list of secret names>
+allow_unauthenticated_receivers = <bool>
+telemetry_index = <string>
+# telemetry_interval defaults to 60. Set it to 0 to stop sending the metrics of the input to telemetry_index.
+telemetry_interval = <seconds>
+persistent_queue = <bool>
+output_mode = <stdout|hec>
+hec_endpoint = <url>
+hec_token = <secret name>
+hec_use_ack = <bool>
+hec_ca_file = <string>
+shutdown_timeout = <seconds>
+reload_interval = <seconds>
+log_level = <debug|info|warn|error>
+log_format = <json|splunkd>
+capture = <bool>
+capture_max_size = <megabytes>
+capture_max_files = <integer>